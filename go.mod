@@ -2,4 +2,8 @@ module github.com/teivah/100-go-mistakes
 
 go 1.18
 
-require golang.org/x/sync v0.0.0-20210220032951-036812b2e83c
+require (
+	github.com/mattn/go-sqlite3 v1.14.16
+	golang.org/x/sync v0.0.0-20210220032951-036812b2e83c
+	golang.org/x/text v0.14.0
+)
@@ -0,0 +1,84 @@
+// Package httpclient creates HTTP clients from a configuration, composing
+// round-tripper middlewares for tracing, logging, retries and timeouts.
+package httpclient
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"time"
+)
+
+// Config describes a client. The zero value is a client equivalent to
+// http.DefaultClient.
+type Config struct {
+	// Tracing sets the trace and span ID headers on each request.
+	Tracing bool
+	// Logger, if set, logs each attempt of a request.
+	Logger *log.Logger
+	// Retry defines the retries of failed requests; none by default.
+	Retry RetryPolicy
+	// Timeout bounds each attempt, unless the host is listed in HostTimeouts.
+	Timeout      time.Duration
+	HostTimeouts map[string]time.Duration
+	// Transport is the underlying round-tripper, http.DefaultTransport by
+	// default.
+	Transport http.RoundTripper
+}
+
+func (c Config) validate() error {
+	if c.Timeout < 0 {
+		return errors.New("timeout should be positive")
+	}
+	for host, timeout := range c.HostTimeouts {
+		if timeout <= 0 {
+			return errors.New("timeout of host " + host + " should be strictly positive")
+		}
+	}
+	return c.Retry.validate()
+}
+
+// Middleware decorates a round-tripper.
+type Middleware func(http.RoundTripper) http.RoundTripper
+
+// RoundTripperFunc is an adapter to use a function as a round-tripper.
+type RoundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f RoundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// Chain applies the middlewares to rt; the first one is the outermost.
+func Chain(rt http.RoundTripper, middlewares ...Middleware) http.RoundTripper {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		rt = middlewares[i](rt)
+	}
+	return rt
+}
+
+// New returns a client configured by cfg. A request goes through the
+// tracing, then each attempt through the logging and the timeout.
+func New(cfg Config) (*http.Client, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	transport := cfg.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	var middlewares []Middleware
+	if cfg.Tracing {
+		middlewares = append(middlewares, Tracing(nil))
+	}
+	if cfg.Retry.MaxAttempts > 1 {
+		middlewares = append(middlewares, Retry(cfg.Retry))
+	}
+	if cfg.Logger != nil {
+		middlewares = append(middlewares, Logging(cfg.Logger))
+	}
+	if cfg.Timeout > 0 || len(cfg.HostTimeouts) > 0 {
+		middlewares = append(middlewares, HostTimeouts(cfg.Timeout, cfg.HostTimeouts))
+	}
+	return &http.Client{Transport: Chain(transport, middlewares...)}, nil
+}
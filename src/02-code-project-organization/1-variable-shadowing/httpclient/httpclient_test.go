@@ -0,0 +1,258 @@
+package httpclient
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestNew_Default(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(TraceIDHeader) != "" {
+			t.Error("unexpected trace header")
+		}
+		_, _ = io.WriteString(w, "ok")
+	}))
+	defer srv.Close()
+
+	client, err := New(Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := client.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if string(body) != "ok" {
+		t.Errorf("got: %s", body)
+	}
+}
+
+func TestNew_InvalidConfig(t *testing.T) {
+	configs := []Config{
+		{Timeout: -time.Second},
+		{HostTimeouts: map[string]time.Duration{"foo": 0}},
+		{Retry: RetryPolicy{MaxAttempts: -1}},
+		{Retry: RetryPolicy{BaseDelay: time.Second, MaxDelay: time.Millisecond}},
+	}
+	for _, cfg := range configs {
+		if _, err := New(cfg); err == nil {
+			t.Errorf("%+v: expected an error", cfg)
+		}
+	}
+}
+
+func TestTracing(t *testing.T) {
+	var traceIDs, spanIDs []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceIDs = append(traceIDs, r.Header.Get(TraceIDHeader))
+		spanIDs = append(spanIDs, r.Header.Get(SpanIDHeader))
+	}))
+	defer srv.Close()
+
+	client, err := New(Config{Tracing: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	req, _ := http.NewRequestWithContext(WithTraceID(context.Background(), "trace"), http.MethodGet, srv.URL, nil)
+	for i := 0; i < 2; i++ {
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = resp.Body.Close()
+	}
+	if req.Header.Get(SpanIDHeader) != "" {
+		t.Error("caller request mutated")
+	}
+
+	if traceIDs[0] != "trace" || traceIDs[1] != "trace" {
+		t.Errorf("trace IDs: %v", traceIDs)
+	}
+	if len(spanIDs[0]) != 16 || spanIDs[0] == spanIDs[1] {
+		t.Errorf("span IDs: %v", spanIDs)
+	}
+}
+
+func TestLogging(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}))
+	defer srv.Close()
+
+	var buf bytes.Buffer
+	ids := []string{"t", "s"}
+	client := &http.Client{Transport: Chain(http.DefaultTransport,
+		Tracing(func() string {
+			id := ids[0]
+			ids = ids[1:]
+			return id
+		}),
+		Logging(log.New(&buf, "", 0)),
+	)}
+	resp, err := client.Get(srv.URL + "/foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = resp.Body.Close()
+
+	expected := "GET " + srv.URL + "/foo span=s: status=418 duration="
+	if !strings.HasPrefix(buf.String(), expected) {
+		t.Errorf("got: %q", buf.String())
+	}
+}
+
+func TestRetry(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != "payload" {
+			t.Errorf("body: %q", body)
+		}
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = io.WriteString(w, "ok")
+	}))
+	defer srv.Close()
+
+	client, err := New(Config{Retry: RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	req, _ := http.NewRequest(http.MethodPut, srv.URL, strings.NewReader("payload"))
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || calls != 3 {
+		t.Errorf("status: %d, calls: %d", resp.StatusCode, calls)
+	}
+}
+
+func TestRetry_Exhausted(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	client, _ := New(Config{Retry: RetryPolicy{MaxAttempts: 2}})
+	resp, err := client.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusTooManyRequests || calls != 2 {
+		t.Errorf("status: %d, calls: %d", resp.StatusCode, calls)
+	}
+
+	// POST isn't idempotent
+	atomic.StoreInt32(&calls, 0)
+	resp, err = client.Post(srv.URL, "text/plain", strings.NewReader("foo"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = resp.Body.Close()
+	if calls != 1 {
+		t.Errorf("calls: %d", calls)
+	}
+}
+
+func TestRetry_TransportErrorAndCancel(t *testing.T) {
+	var calls int
+	failing := RoundTripperFunc(func(*http.Request) (*http.Response, error) {
+		calls++
+		return nil, errors.New("connection refused")
+	})
+	client := &http.Client{Transport: Retry(RetryPolicy{MaxAttempts: 3})(failing)}
+	if _, err := client.Get("http://example.invalid"); err == nil || calls != 3 {
+		t.Errorf("err: %v, calls: %d", err, calls)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	client = &http.Client{Transport: Retry(RetryPolicy{MaxAttempts: 3, BaseDelay: time.Hour})(failing)}
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "http://example.invalid", nil)
+	start := time.Now()
+	if _, err := client.Do(req); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected a deadline error, got: %v", err)
+	}
+	if time.Since(start) > time.Second {
+		t.Error("backoff not interrupted")
+	}
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	p := RetryPolicy{BaseDelay: time.Second, MaxDelay: 5 * time.Second}
+	expected := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}
+	for n, d := range expected {
+		if got := p.backoff(n); got != d {
+			t.Errorf("%d: got %v, expected %v", n, got, d)
+		}
+	}
+	for i := 0; i < 100; i++ {
+		if d := jitter(time.Second); d < 0 || d > time.Second {
+			t.Fatalf("jitter out of range: %v", d)
+		}
+	}
+}
+
+func TestHostTimeouts(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(time.Second):
+		case <-r.Context().Done():
+		}
+	}))
+	defer srv.Close()
+	u, _ := url.Parse(srv.URL)
+
+	client, err := New(Config{
+		Timeout:      time.Minute,
+		HostTimeouts: map[string]time.Duration{u.Hostname(): 20 * time.Millisecond},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	_, err = client.Get(srv.URL)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected a deadline error, got: %v", err)
+	}
+	if time.Since(start) > 500*time.Millisecond {
+		t.Error("host timeout not applied")
+	}
+}
+
+func TestHostTimeouts_BodyReadable(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, "ok")
+	}))
+	defer srv.Close()
+
+	client, _ := New(Config{Timeout: time.Second})
+	resp, err := client.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil || string(body) != "ok" {
+		t.Errorf("body: %q, err: %v", body, err)
+	}
+}
@@ -0,0 +1,110 @@
+package httpclient
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	"log"
+	"net/http"
+	"time"
+)
+
+const (
+	TraceIDHeader = "X-Trace-Id"
+	SpanIDHeader  = "X-Span-Id"
+)
+
+type traceIDKey struct{}
+
+// WithTraceID returns a context whose requests are traced under id, for
+// example the trace ID of an incoming request.
+func WithTraceID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, traceIDKey{}, id)
+}
+
+func randomID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// Tracing sets a new span ID header on each request, and a trace ID header
+// taken from the context (see WithTraceID) or else generated. newID defaults
+// to random 16-character hexadecimal IDs.
+func Tracing(newID func() string) Middleware {
+	if newID == nil {
+		newID = randomID
+	}
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			traceID, _ := req.Context().Value(traceIDKey{}).(string)
+			if traceID == "" {
+				traceID = newID()
+			}
+			req = req.Clone(req.Context())
+			req.Header.Set(TraceIDHeader, traceID)
+			req.Header.Set(SpanIDHeader, newID())
+			return next.RoundTrip(req)
+		})
+	}
+}
+
+// Logging logs the method, URL, span ID if any, outcome and duration of
+// each request.
+func Logging(logger *log.Logger) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			start := time.Now()
+			resp, err := next.RoundTrip(req)
+			span := ""
+			if id := req.Header.Get(SpanIDHeader); id != "" {
+				span = " span=" + id
+			}
+			if err != nil {
+				logger.Printf("%s %s%s: error=%v duration=%v", req.Method, req.URL, span, err, time.Since(start))
+			} else {
+				logger.Printf("%s %s%s: status=%d duration=%v", req.Method, req.URL, span, resp.StatusCode, time.Since(start))
+			}
+			return resp, err
+		})
+	}
+}
+
+// HostTimeouts bounds each request by the timeout of its host (without the
+// port), or else by def if strictly positive. The timeout also covers the
+// reading of the response body.
+func HostTimeouts(def time.Duration, timeouts map[string]time.Duration) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			timeout, ok := timeouts[req.URL.Hostname()]
+			if !ok {
+				timeout = def
+			}
+			if timeout <= 0 {
+				return next.RoundTrip(req)
+			}
+
+			ctx, cancel := context.WithTimeout(req.Context(), timeout)
+			resp, err := next.RoundTrip(req.WithContext(ctx))
+			if err != nil {
+				cancel()
+				return nil, err
+			}
+			resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: cancel}
+			return resp, nil
+		})
+	}
+}
+
+// cancelBody releases the context of a request once its body is closed.
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
@@ -0,0 +1,115 @@
+package httpclient
+
+import (
+	"errors"
+	"io"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+// RetryPolicy defines how failed requests are retried: transport errors, 429
+// and 5xx responses. Only the idempotent methods are retried, and only if
+// their body can be replayed (see http.Request.GetBody).
+type RetryPolicy struct {
+	// MaxAttempts counts the first attempt; 0 and 1 disable the retries.
+	MaxAttempts int
+	// BaseDelay is the backoff before the first retry, doubled for each
+	// next one up to MaxDelay. The actual delay is randomly picked between 0
+	// and this backoff ("full jitter").
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+}
+
+func (p RetryPolicy) validate() error {
+	if p.MaxAttempts < 0 {
+		return errors.New("max attempts should be positive")
+	}
+	if p.BaseDelay < 0 || p.MaxDelay < 0 {
+		return errors.New("retry delays should be positive")
+	}
+	if p.MaxDelay != 0 && p.MaxDelay < p.BaseDelay {
+		return errors.New("max delay should be greater than the base delay")
+	}
+	return nil
+}
+
+// backoff returns the maximal delay before the nth retry, starting at 0.
+func (p RetryPolicy) backoff(n int) time.Duration {
+	d := p.BaseDelay
+	for i := 0; i < n; i++ {
+		if p.MaxDelay != 0 && d >= p.MaxDelay/2 {
+			return p.MaxDelay
+		}
+		d *= 2
+	}
+	if p.MaxDelay != 0 && d > p.MaxDelay {
+		return p.MaxDelay
+	}
+	return d
+}
+
+func jitter(d time.Duration) time.Duration {
+	if d <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(d) + 1))
+}
+
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace,
+		http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+func retryable(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+}
+
+// Retry retries the failed requests according to policy. The last response
+// or error is returned once the attempts are exhausted.
+func Retry(policy RetryPolicy) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			replayable := req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+			if !idempotent(req.Method) || !replayable {
+				return next.RoundTrip(req)
+			}
+
+			for attempt := 1; ; attempt++ {
+				r := req.Clone(req.Context())
+				if attempt > 1 && req.GetBody != nil {
+					body, err := req.GetBody()
+					if err != nil {
+						return nil, err
+					}
+					r.Body = body
+				}
+
+				resp, err := next.RoundTrip(r)
+				if attempt >= policy.MaxAttempts || !retryable(resp, err) {
+					return resp, err
+				}
+				if resp != nil {
+					// Drain the body so that the connection can be reused
+					_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+					_ = resp.Body.Close()
+				}
+
+				timer := time.NewTimer(jitter(policy.backoff(attempt - 1)))
+				select {
+				case <-timer.C:
+				case <-req.Context().Done():
+					timer.Stop()
+					return nil, req.Context().Err()
+				}
+			}
+		})
+	}
+}
@@ -3,6 +3,8 @@ package main
 import (
 	"log"
 	"net/http"
+
+	"github.com/teivah/100-go-mistakes/src/02-code-project-organization/1-variable-shadowing/httpclient"
 )
 
 func main() {
@@ -10,11 +12,12 @@ func main() {
 	_ = listing2()
 	_ = listing3()
 	_ = listing4()
+	_ = listing5()
 }
 
 func listing1() error {
 	var client *http.Client
-	if tracing {
+	if config.Tracing {
 		client, err := createClientWithTracing()
 		if err != nil {
 			return err
@@ -34,7 +37,7 @@ func listing1() error {
 
 func listing2() error {
 	var client *http.Client
-	if tracing {
+	if config.Tracing {
 		c, err := createClientWithTracing()
 		if err != nil {
 			return err
@@ -55,7 +58,7 @@ func listing2() error {
 func listing3() error {
 	var client *http.Client
 	var err error
-	if tracing {
+	if config.Tracing {
 		client, err = createClientWithTracing()
 		if err != nil {
 			return err
@@ -74,7 +77,7 @@ func listing3() error {
 func listing4() error {
 	var client *http.Client
 	var err error
-	if tracing {
+	if config.Tracing {
 		client, err = createClientWithTracing()
 	} else {
 		client, err = createDefaultClient()
@@ -87,12 +90,28 @@ func listing4() error {
 	return nil
 }
 
-var tracing bool
+// listing5 avoids the branches altogether, as tracing is part of the
+// configuration.
+func listing5() error {
+	client, err := httpclient.New(config)
+	if err != nil {
+		return err
+	}
+
+	_ = client
+	return nil
+}
+
+var config httpclient.Config
 
 func createClientWithTracing() (*http.Client, error) {
-	return nil, nil
+	cfg := config
+	cfg.Tracing = true
+	return httpclient.New(cfg)
 }
 
 func createDefaultClient() (*http.Client, error) {
-	return nil, nil
+	cfg := config
+	cfg.Tracing = false
+	return httpclient.New(cfg)
 }
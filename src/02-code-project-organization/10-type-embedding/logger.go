@@ -0,0 +1,188 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Level int
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+func (l Level) String() string {
+	switch l {
+	case LevelDebug:
+		return "debug"
+	case LevelInfo:
+		return "info"
+	case LevelWarn:
+		return "warn"
+	case LevelError:
+		return "error"
+	default:
+		return "level(" + strconv.Itoa(int(l)) + ")"
+	}
+}
+
+type Field struct {
+	Key   string
+	Value any
+}
+
+type Record struct {
+	Time   time.Time
+	Level  Level
+	Msg    string
+	Fields []Field
+}
+
+// Encoder appends the encoding of a record, including the trailing newline,
+// to buf.
+type Encoder interface {
+	Encode(buf []byte, r Record) []byte
+}
+
+// Logger is a structured logger. It's still an io.WriteCloser forwarding
+// Write and Close to the underlying writer, and its zero value with a
+// writeCloser set logs every level as text.
+type Logger struct {
+	writeCloser io.WriteCloser
+	level       Level
+	encoder     Encoder
+	fields      []Field
+	now         func() time.Time
+}
+
+func NewLogger(w io.WriteCloser, level Level, encoder Encoder) Logger {
+	return Logger{writeCloser: w, level: level, encoder: encoder}
+}
+
+func (l Logger) Write(p []byte) (int, error) {
+	return l.writeCloser.Write(p)
+}
+
+func (l Logger) Close() error {
+	return l.writeCloser.Close()
+}
+
+// With returns a logger adding the key/value pairs to each record.
+func (l Logger) With(keyValues ...any) Logger {
+	fields := make([]Field, 0, len(l.fields)+len(keyValues)/2)
+	fields = append(fields, l.fields...)
+	l.fields = appendFields(fields, keyValues)
+	return l
+}
+
+func (l Logger) Debug(msg string, keyValues ...any) { l.log(LevelDebug, msg, keyValues) }
+func (l Logger) Info(msg string, keyValues ...any)  { l.log(LevelInfo, msg, keyValues) }
+func (l Logger) Warn(msg string, keyValues ...any)  { l.log(LevelWarn, msg, keyValues) }
+func (l Logger) Error(msg string, keyValues ...any) { l.log(LevelError, msg, keyValues) }
+
+func (l Logger) log(level Level, msg string, keyValues []any) {
+	if level < l.level {
+		return
+	}
+	now := time.Now
+	if l.now != nil {
+		now = l.now
+	}
+	encoder := l.encoder
+	if encoder == nil {
+		encoder = TextEncoder{}
+	}
+
+	fields := make([]Field, 0, len(l.fields)+len(keyValues)/2)
+	fields = append(fields, l.fields...)
+	r := Record{
+		Time:   now(),
+		Level:  level,
+		Msg:    msg,
+		Fields: appendFields(fields, keyValues),
+	}
+	// A single Write per record so that concurrent records don't interleave
+	_, _ = l.writeCloser.Write(encoder.Encode(nil, r))
+}
+
+// appendFields converts key/value pairs. Non-string keys are formatted with
+// %v, and a trailing key without value gets the "!MISSING" value.
+func appendFields(fields []Field, keyValues []any) []Field {
+	for i := 0; i < len(keyValues); i += 2 {
+		key, ok := keyValues[i].(string)
+		if !ok {
+			key = fmt.Sprintf("%v", keyValues[i])
+		}
+		var value any = "!MISSING"
+		if i+1 < len(keyValues) {
+			value = keyValues[i+1]
+		}
+		fields = append(fields, Field{Key: key, Value: value})
+	}
+	return fields
+}
+
+// TextEncoder encodes records as logfmt-like lines:
+// time=... level=info msg="started" port=8080
+type TextEncoder struct{}
+
+func (TextEncoder) Encode(buf []byte, r Record) []byte {
+	buf = append(buf, "time="...)
+	buf = r.Time.AppendFormat(buf, time.RFC3339Nano)
+	buf = append(buf, " level="...)
+	buf = append(buf, r.Level.String()...)
+	buf = append(buf, " msg="...)
+	buf = appendTextValue(buf, r.Msg)
+	for _, f := range r.Fields {
+		buf = append(buf, ' ')
+		buf = append(buf, f.Key...)
+		buf = append(buf, '=')
+		buf = appendTextValue(buf, fmt.Sprint(f.Value))
+	}
+	return append(buf, '\n')
+}
+
+func appendTextValue(buf []byte, s string) []byte {
+	if s == "" || strings.ContainsAny(s, " =\"\t\r\n") {
+		return strconv.AppendQuote(buf, s)
+	}
+	return append(buf, s...)
+}
+
+// JSONEncoder encodes records as one JSON object per line. Fields that
+// can't be marshaled are encoded using their fmt representation.
+type JSONEncoder struct{}
+
+func (JSONEncoder) Encode(buf []byte, r Record) []byte {
+	buf = append(buf, `{"time":`...)
+	buf = strconv.AppendQuote(buf, r.Time.Format(time.RFC3339Nano))
+	buf = append(buf, `,"level":`...)
+	buf = strconv.AppendQuote(buf, r.Level.String())
+	buf = append(buf, `,"msg":`...)
+	buf = appendJSONValue(buf, r.Msg)
+	for _, f := range r.Fields {
+		buf = append(buf, ',')
+		buf = appendJSONValue(buf, f.Key)
+		buf = append(buf, ':')
+		buf = appendJSONValue(buf, f.Value)
+	}
+	return append(buf, "}\n"...)
+}
+
+func appendJSONValue(buf []byte, v any) []byte {
+	if err, ok := v.(error); ok {
+		v = err.Error()
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		data, _ = json.Marshal(fmt.Sprint(v))
+	}
+	return append(buf, data...)
+}
@@ -0,0 +1,240 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type nopCloser struct {
+	bytes.Buffer
+}
+
+func (nopCloser) Close() error { return nil }
+
+func testLogger(level Level, encoder Encoder) (Logger, *nopCloser) {
+	w := &nopCloser{}
+	l := NewLogger(w, level, encoder)
+	l.now = func() time.Time {
+		return time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC)
+	}
+	return l, w
+}
+
+func TestLogger_Text(t *testing.T) {
+	l, w := testLogger(LevelInfo, TextEncoder{})
+	l = l.With("service", "api")
+	l.Debug("skipped")
+	l.Info("request served", "path", "/users", "status", 200, "odd")
+
+	expected := `time=2022-01-02T03:04:05Z level=info msg="request served" service=api path=/users status=200 odd=!MISSING` + "\n"
+	if w.String() != expected {
+		t.Errorf("got: %s, expected: %s", w.String(), expected)
+	}
+}
+
+func TestLogger_JSON(t *testing.T) {
+	l, w := testLogger(LevelDebug, JSONEncoder{})
+	l.With("service", "api").Error("failed", "err", errors.New("foo"), "retries", 3)
+
+	expected := `{"time":"2022-01-02T03:04:05Z","level":"error","msg":"failed","service":"api","err":"foo","retries":3}` + "\n"
+	if w.String() != expected {
+		t.Errorf("got: %s, expected: %s", w.String(), expected)
+	}
+}
+
+func TestLogger_WithDoesNotShareFields(t *testing.T) {
+	l, w := testLogger(LevelDebug, TextEncoder{})
+	base := l.With("a", 1)
+	_ = base.With("b", 2)
+	base.With("c", 3).Info("x")
+	if bytes.Contains(w.Bytes(), []byte("b=2")) {
+		t.Errorf("fields leaked between loggers: %s", w.String())
+	}
+}
+
+func TestLogger_WriteCloser(t *testing.T) {
+	w := &nopCloser{}
+	l := Logger{writeCloser: w}
+	if _, err := l.Write([]byte("foo")); err != nil {
+		t.Fatal(err)
+	}
+	l.Debug("bar")
+	if !bytes.HasPrefix(w.Bytes(), []byte("footime=")) {
+		t.Errorf("got: %s", w.String())
+	}
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestRotatingFile_Size(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	f, err := OpenRotatingFile(path, 10, 0, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	for _, s := range []string{"aaaaaa\n", "bbbbbb\n", "cccccc\n", "dddddd\n"} {
+		if _, err := f.Write([]byte(s)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if got := readFile(t, path); got != "dddddd\n" {
+		t.Errorf("current: %q", got)
+	}
+	if got := readFile(t, path+".1"); got != "cccccc\n" {
+		t.Errorf("backup 1: %q", got)
+	}
+	if got := readFile(t, path+".2"); got != "bbbbbb\n" {
+		t.Errorf("backup 2: %q", got)
+	}
+	if _, err := os.Stat(path + ".3"); !errors.Is(err, os.ErrNotExist) {
+		t.Error("expected only 2 backups")
+	}
+}
+
+func TestRotatingFile_Age(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	f, err := OpenRotatingFile(path, 0, time.Hour, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	now := time.Now()
+	f.now = func() time.Time { return now }
+	f.startedAt = now
+
+	l := NewLogger(f, LevelInfo, JSONEncoder{})
+	l.Info("first")
+	now = now.Add(time.Hour)
+	l.Info("second")
+
+	if got := readFile(t, path); !bytes.Contains([]byte(got), []byte("second")) {
+		t.Errorf("current: %q", got)
+	}
+	if got := readFile(t, path+".1"); !bytes.Contains([]byte(got), []byte("first")) {
+		t.Errorf("backup: %q", got)
+	}
+}
+
+func TestRotatingFile_AgeOfExistingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	if err := os.WriteFile(path, []byte("old\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	past := time.Now().Add(-2 * time.Hour)
+	if err := os.Chtimes(path, past, past); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := OpenRotatingFile(path, 0, time.Hour, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := f.Write([]byte("new\n")); err != nil {
+		t.Fatal(err)
+	}
+	if got := readFile(t, path); got != "new\n" {
+		t.Errorf("current: %q", got)
+	}
+	if got := readFile(t, path+".1"); got != "old\n" {
+		t.Errorf("backup: %q", got)
+	}
+}
+
+func TestRotatingFile_FailedRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	f, err := OpenRotatingFile(path, 0, 0, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	// A non-empty directory in place of the backup can't be removed
+	if err := os.MkdirAll(filepath.Join(path+".1", "foo"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := f.Write([]byte("a\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Rotate(); err == nil {
+		t.Fatal("expected the rotation to fail")
+	}
+	if _, err := f.Write([]byte("b\n")); err != nil {
+		t.Fatalf("expected the file to be reopened: %v", err)
+	}
+	if got := readFile(t, path); got != "a\nb\n" {
+		t.Errorf("current: %q", got)
+	}
+}
+
+func TestRotatingFile_FailedSizeRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	f, err := OpenRotatingFile(path, 4, 0, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	now := time.Now()
+	f.now = func() time.Time { return now }
+	backup := filepath.Join(path+".1", "foo")
+	if err := os.MkdirAll(backup, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, s := range []string{"a\n", "b\n", "c\n"} {
+		if n, err := f.Write([]byte(s)); err != nil || n != len(s) {
+			t.Fatalf("write %q: %d, %v", s, n, err)
+		}
+	}
+	if got := readFile(t, path); got != "a\nb\nc\n" {
+		t.Errorf("current: %q", got)
+	}
+	if f.Err() == nil {
+		t.Error("expected the rotation error to be reported")
+	}
+
+	// Retried once the delay elapsed
+	if err := os.RemoveAll(path + ".1"); err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(rotateRetryDelay)
+	if _, err := f.Write([]byte("d\n")); err != nil {
+		t.Fatal(err)
+	}
+	if f.Err() != nil {
+		t.Errorf("unexpected error: %v", f.Err())
+	}
+	if got := readFile(t, path+".1"); got != "a\nb\nc\n" {
+		t.Errorf("backup: %q", got)
+	}
+}
+
+func TestRotatingFile_Closed(t *testing.T) {
+	f, err := OpenRotatingFile(filepath.Join(t.TempDir(), "app.log"), 0, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Write([]byte("foo")); !errors.Is(err, os.ErrClosed) {
+		t.Errorf("expected ErrClosed, got: %v", err)
+	}
+}
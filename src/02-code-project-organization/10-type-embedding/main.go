@@ -1,9 +1,10 @@
 package main
 
 import (
-	"io"
+	"fmt"
 	"os"
-	"sync"
+
+	"github.com/teivah/100-go-mistakes/src/02-code-project-organization/10-type-embedding/store"
 )
 
 type Foo struct {
@@ -19,35 +20,11 @@ func fooBar() {
 	foo.Baz = 42
 }
 
-type InMem struct {
-	sync.Mutex
-	m map[string]int
-}
-
-func New() *InMem {
-	return &InMem{m: make(map[string]int)}
-}
-
-func (i *InMem) Get(key string) (int, bool) {
-	i.Lock()
-	v, contains := i.m[key]
-	i.Unlock()
-	return v, contains
-}
-
-type Logger struct {
-	writeCloser io.WriteCloser
-}
-
-func (l Logger) Write(p []byte) (int, error) {
-	return l.writeCloser.Write(p)
-}
-
-func (l Logger) Close() error {
-	return l.writeCloser.Close()
-}
-
 func main() {
+	s := store.New()
+	s.Put("foo", 42)
+	fmt.Println(s.Get("foo"))
+
 	l := Logger{writeCloser: os.Stdout}
 	_, _ = l.Write([]byte("foo"))
 	_ = l.Close()
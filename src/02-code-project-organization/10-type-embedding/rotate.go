@@ -0,0 +1,184 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RotatingFile is an io.WriteCloser writing to a file that is rolled over
+// once it reaches MaxSize bytes or gets older than MaxAge. The creation time
+// of a file isn't portably available, so an existing file is aged from its
+// last modification when it's opened: after a restart, it's rotated at most
+// MaxAge after its last write rather than after its creation. The backups
+// are named path.1 (the most recent) to path.N, and only MaxBackups are
+// kept.
+//
+// A failed rotation doesn't lose the writes, which carry on in the current
+// file; the error is reported by Err and the rotation is retried after
+// rotateRetryDelay.
+type RotatingFile struct {
+	path       string
+	maxSize    int64
+	maxAge     time.Duration
+	maxBackups int
+	now        func() time.Time
+
+	mu        sync.Mutex
+	file      *os.File
+	size      int64
+	startedAt time.Time
+	rotateErr error
+	retryAt   time.Time
+}
+
+// rotateRetryDelay avoids trying to rotate on each write while the rotation
+// keeps failing, e.g. because of permissions.
+const rotateRetryDelay = time.Minute
+
+// OpenRotatingFile opens or creates the file at path. A maxSize or maxAge of
+// 0 disables the corresponding rotation.
+func OpenRotatingFile(path string, maxSize int64, maxAge time.Duration, maxBackups int) (*RotatingFile, error) {
+	if maxSize < 0 || maxAge < 0 || maxBackups < 0 {
+		return nil, errors.New("rotation limits should be positive")
+	}
+	f := &RotatingFile{
+		path:       path,
+		maxSize:    maxSize,
+		maxAge:     maxAge,
+		maxBackups: maxBackups,
+		now:        time.Now,
+	}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (f *RotatingFile) open() error {
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+	f.file = file
+	f.size = info.Size()
+	f.startedAt = f.now()
+	if f.size > 0 {
+		f.startedAt = info.ModTime()
+	}
+	return nil
+}
+
+func (f *RotatingFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.file == nil {
+		return 0, os.ErrClosed
+	}
+
+	if f.shouldRotate(int64(len(p))) && !f.now().Before(f.retryAt) {
+		if err := f.rotate(); err != nil {
+			f.rotateErr = fmt.Errorf("rotating %s: %w", f.path, err)
+			f.retryAt = f.now().Add(rotateRetryDelay)
+			if f.file == nil {
+				return 0, f.rotateErr
+			}
+		} else {
+			f.rotateErr = nil
+		}
+	}
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+func (f *RotatingFile) shouldRotate(n int64) bool {
+	// An empty file is never rotated, even if a single write exceeds maxSize
+	if f.size == 0 {
+		return false
+	}
+	if f.maxSize > 0 && f.size+n > f.maxSize {
+		return true
+	}
+	return f.maxAge > 0 && f.now().Sub(f.startedAt) >= f.maxAge
+}
+
+// Err returns the error of the last rotation, if it failed.
+func (f *RotatingFile) Err() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.rotateErr
+}
+
+// Rotate forces a rollover.
+func (f *RotatingFile) Rotate() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.file == nil {
+		return os.ErrClosed
+	}
+	return f.rotate()
+}
+
+// rotate closes the file, shifts the backups and opens a new file. If the
+// rotation fails, the current file is reopened so that the writes carry on.
+func (f *RotatingFile) rotate() error {
+	err := f.file.Close()
+	f.file = nil
+	if err == nil {
+		err = f.shift()
+	}
+	if err != nil {
+		if openErr := f.open(); openErr != nil {
+			return fmt.Errorf("%w (reopening: %v)", err, openErr)
+		}
+		return err
+	}
+	return f.open()
+}
+
+// shift renames path to path.1, path.1 to path.2 and so on, dropping the
+// oldest backup.
+func (f *RotatingFile) shift() error {
+	if f.maxBackups == 0 {
+		if err := os.Remove(f.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+
+	oldest := f.backup(f.maxBackups)
+	if err := os.Remove(oldest); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	for i := f.maxBackups - 1; i >= 1; i-- {
+		err := os.Rename(f.backup(i), f.backup(i+1))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	return os.Rename(f.path, f.backup(1))
+}
+
+func (f *RotatingFile) backup(i int) string {
+	return f.path + "." + strconv.Itoa(i)
+}
+
+func (f *RotatingFile) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.file == nil {
+		return os.ErrClosed
+	}
+	err := f.file.Close()
+	f.file = nil
+	return err
+}
@@ -0,0 +1,219 @@
+// Package store provides a concurrent in-memory key/value store.
+package store
+
+import (
+	"encoding/gob"
+	"hash/fnv"
+	"io"
+	"sync"
+	"time"
+)
+
+// InMem is a concurrent key/value store. Unlike embedding sync.Mutex, the
+// locks are unexported fields so that Lock and Unlock aren't part of the
+// API.
+type InMem struct {
+	shards []*shard
+	now    func() time.Time
+}
+
+type shard struct {
+	mu sync.Mutex
+	m  map[string]entry
+}
+
+type entry struct {
+	value    int
+	expireAt time.Time // Zero if the entry doesn't expire
+}
+
+func (e entry) expired(now time.Time) bool {
+	return !e.expireAt.IsZero() && !now.Before(e.expireAt)
+}
+
+// New returns a store protected by a single lock.
+func New() *InMem {
+	return NewSharded(1)
+}
+
+// NewSharded returns a store split into n shards, each one with its own lock,
+// to reduce contention when accessed by many goroutines.
+func NewSharded(n int) *InMem {
+	if n < 1 {
+		n = 1
+	}
+	shards := make([]*shard, n)
+	for i := range shards {
+		shards[i] = &shard{m: make(map[string]entry)}
+	}
+	return &InMem{shards: shards, now: time.Now}
+}
+
+func (i *InMem) shard(key string) *shard {
+	return i.shards[i.shardIndex(key)]
+}
+
+func (i *InMem) shardIndex(key string) int {
+	if len(i.shards) == 1 {
+		return 0
+	}
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+	return int(h.Sum32() % uint32(len(i.shards)))
+}
+
+func (i *InMem) Get(key string) (int, bool) {
+	s := i.shard(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, contains := s.get(key, i.now())
+	return e.value, contains
+}
+
+// get returns the entry of key, deleting it if expired. s.mu must be held.
+func (s *shard) get(key string, now time.Time) (entry, bool) {
+	e, contains := s.m[key]
+	if !contains {
+		return entry{}, false
+	}
+	if e.expired(now) {
+		delete(s.m, key)
+		return entry{}, false
+	}
+	return e, true
+}
+
+func (i *InMem) Put(key string, value int) {
+	i.PutWithTTL(key, value, 0)
+}
+
+// PutWithTTL stores value for the given duration. A ttl <= 0 means no
+// expiry.
+func (i *InMem) PutWithTTL(key string, value int, ttl time.Duration) {
+	e := entry{value: value}
+	if ttl > 0 {
+		e.expireAt = i.now().Add(ttl)
+	}
+	s := i.shard(key)
+	s.mu.Lock()
+	s.m[key] = e
+	s.mu.Unlock()
+}
+
+// Delete removes key and returns whether it was present.
+func (i *InMem) Delete(key string) bool {
+	s := i.shard(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, contains := s.get(key, i.now())
+	delete(s.m, key)
+	return contains
+}
+
+// CompareAndSwap sets key to new only if its current value is old. The TTL
+// of the entry is kept.
+func (i *InMem) CompareAndSwap(key string, old, new int) bool {
+	s := i.shard(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, contains := s.get(key, i.now())
+	if !contains || e.value != old {
+		return false
+	}
+	e.value = new
+	s.m[key] = e
+	return true
+}
+
+// Len returns the number of entries, including expired ones not yet
+// removed.
+func (i *InMem) Len() int {
+	n := 0
+	for _, s := range i.shards {
+		s.mu.Lock()
+		n += len(s.m)
+		s.mu.Unlock()
+	}
+	return n
+}
+
+// DeleteExpired removes the expired entries and returns how many.
+func (i *InMem) DeleteExpired() int {
+	now := i.now()
+	n := 0
+	for _, s := range i.shards {
+		s.mu.Lock()
+		for k, e := range s.m {
+			if e.expired(now) {
+				delete(s.m, k)
+				n++
+			}
+		}
+		s.mu.Unlock()
+	}
+	return n
+}
+
+type record struct {
+	Key      string
+	Value    int
+	ExpireAt time.Time
+}
+
+// Snapshot writes the live entries to w. All the shards are locked while
+// the entries are copied so that the snapshot is consistent, but not during
+// the encoding, which may block on w.
+func (i *InMem) Snapshot(w io.Writer) error {
+	records := i.records()
+	return gob.NewEncoder(w).Encode(records)
+}
+
+// records returns a copy of the live entries, taken with all the shards
+// locked.
+func (i *InMem) records() []record {
+	for _, s := range i.shards {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+	}
+
+	now := i.now()
+	var records []record
+	for _, s := range i.shards {
+		for k, e := range s.m {
+			if e.expired(now) {
+				continue
+			}
+			records = append(records, record{Key: k, Value: e.value, ExpireAt: e.expireAt})
+		}
+	}
+	return records
+}
+
+// Restore replaces the content of the store with a snapshot read from r.
+// Entries that expired since the snapshot are skipped.
+func (i *InMem) Restore(r io.Reader) error {
+	var records []record
+	if err := gob.NewDecoder(r).Decode(&records); err != nil {
+		return err
+	}
+
+	maps := make([]map[string]entry, len(i.shards))
+	for j := range maps {
+		maps[j] = make(map[string]entry)
+	}
+	now := i.now()
+	for _, rec := range records {
+		e := entry{value: rec.Value, expireAt: rec.ExpireAt}
+		if e.expired(now) {
+			continue
+		}
+		maps[i.shardIndex(rec.Key)][rec.Key] = e
+	}
+
+	for j, s := range i.shards {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.m = maps[j]
+	}
+	return nil
+}
@@ -0,0 +1,195 @@
+package store
+
+import (
+	"bytes"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestInMem(t *testing.T) {
+	stores := map[string]*InMem{
+		"single lock": New(),
+		"sharded":     NewSharded(8),
+	}
+	for name, store := range stores {
+		store := store
+		t.Run(name, func(t *testing.T) {
+			store.Put("a", 1)
+			if v, ok := store.Get("a"); !ok || v != 1 {
+				t.Errorf("got: %d, %t", v, ok)
+			}
+			if store.CompareAndSwap("a", 2, 3) {
+				t.Error("unexpected swap")
+			}
+			if !store.CompareAndSwap("a", 1, 2) {
+				t.Error("expected a swap")
+			}
+			if v, _ := store.Get("a"); v != 2 {
+				t.Errorf("got: %d", v)
+			}
+			if !store.Delete("a") || store.Delete("a") {
+				t.Error("unexpected delete result")
+			}
+			if _, ok := store.Get("a"); ok {
+				t.Error("expected a missing key")
+			}
+		})
+	}
+}
+
+func TestInMem_TTL(t *testing.T) {
+	now := time.Now()
+	store := New()
+	store.now = func() time.Time { return now }
+
+	store.PutWithTTL("a", 1, time.Minute)
+	store.PutWithTTL("b", 2, time.Hour)
+	store.Put("c", 3)
+
+	now = now.Add(time.Minute)
+	if _, ok := store.Get("a"); ok {
+		t.Error("expected a to be expired")
+	}
+	if store.CompareAndSwap("a", 1, 2) {
+		t.Error("unexpected swap on an expired key")
+	}
+
+	now = now.Add(time.Hour)
+	if n := store.DeleteExpired(); n != 1 {
+		t.Errorf("expected b to be deleted, got %d deletions", n)
+	}
+	if store.Len() != 1 {
+		t.Errorf("len: %d", store.Len())
+	}
+}
+
+func TestInMem_SnapshotRestore(t *testing.T) {
+	now := time.Now()
+	src := NewSharded(4)
+	src.now = func() time.Time { return now }
+	for i := 0; i < 100; i++ {
+		src.Put(strconv.Itoa(i), i)
+	}
+	src.PutWithTTL("short", 1, time.Second)
+	src.PutWithTTL("long", 2, time.Hour)
+
+	var buf bytes.Buffer
+	if err := src.Snapshot(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	// The shards count doesn't have to match
+	dst := New()
+	dst.Put("stale", 0)
+	dst.now = func() time.Time { return now.Add(time.Minute) }
+	if err := dst.Restore(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := dst.Get("stale"); ok {
+		t.Error("expected restore to replace the content")
+	}
+	if _, ok := dst.Get("short"); ok {
+		t.Error("expected short to be expired")
+	}
+	if v, ok := dst.Get("long"); !ok || v != 2 {
+		t.Errorf("long: got %d, %t", v, ok)
+	}
+	for i := 0; i < 100; i++ {
+		if v, ok := dst.Get(strconv.Itoa(i)); !ok || v != i {
+			t.Fatalf("%d: got %d, %t", i, v, ok)
+		}
+	}
+}
+
+func TestInMem_SnapshotEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	if err := New().Snapshot(&buf); err != nil {
+		t.Fatal(err)
+	}
+	store := New()
+	store.Put("a", 1)
+	if err := store.Restore(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if store.Len() != 0 {
+		t.Errorf("len: %d", store.Len())
+	}
+}
+
+// putWriter updates the store while the snapshot is written.
+type putWriter struct {
+	store *InMem
+	bytes.Buffer
+}
+
+func (w *putWriter) Write(p []byte) (int, error) {
+	w.store.Put("written", 1)
+	return w.Buffer.Write(p)
+}
+
+func TestInMem_SnapshotUnlockedWrite(t *testing.T) {
+	store := NewSharded(4)
+	store.Put("a", 1)
+	// Would deadlock if the shards were still locked during the encoding
+	if err := store.Snapshot(&putWriter{store: store}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestInMem_Concurrent(t *testing.T) {
+	store := NewSharded(4)
+	store.Put("counter", 0)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				for {
+					v, _ := store.Get("counter")
+					if store.CompareAndSwap("counter", v, v+1) {
+						break
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if v, _ := store.Get("counter"); v != 800 {
+		t.Errorf("got: %d", v)
+	}
+}
+
+const keys = 1024
+
+func benchmarkInMem(b *testing.B, store *InMem) {
+	names := make([]string, keys)
+	for i := range names {
+		names[i] = strconv.Itoa(i)
+		store.Put(names[i], i)
+	}
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			key := names[i%keys]
+			if i%4 == 0 {
+				store.Put(key, i)
+			} else {
+				store.Get(key)
+			}
+			i++
+		}
+	})
+}
+
+func BenchmarkInMemSingleLock(b *testing.B) {
+	benchmarkInMem(b, New())
+}
+
+func BenchmarkInMemSharded(b *testing.B) {
+	benchmarkInMem(b, NewSharded(32))
+}
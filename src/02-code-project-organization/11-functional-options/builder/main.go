@@ -1,18 +1,29 @@
-package main
+package builder
 
 import (
 	"errors"
-	"net/http"
-)
+	"time"
 
-const defaultHTTPPort = 8080
+	"github.com/teivah/100-go-mistakes/src/02-code-project-organization/11-functional-options/config"
+)
 
-type Config struct {
-	Port int
+type ConfigBuilder struct {
+	base         *config.Config
+	port         *int
+	readTimeout  *time.Duration
+	writeTimeout *time.Duration
+	idleTimeout  *time.Duration
+	maxHeader    *int
+	certFile     *string
+	keyFile      *string
+	logLevel     *string
 }
 
-type ConfigBuilder struct {
-	port *int
+// From sets the configuration the builder starts from, for example the
+// result of config.Load. Otherwise, config.Default is used.
+func (b *ConfigBuilder) From(cfg config.Config) *ConfigBuilder {
+	b.base = &cfg
+	return b
 }
 
 func (b *ConfigBuilder) Port(port int) *ConfigBuilder {
@@ -20,26 +31,79 @@ func (b *ConfigBuilder) Port(port int) *ConfigBuilder {
 	return b
 }
 
-func (b *ConfigBuilder) Build() (Config, error) {
-	cfg := Config{}
-
-	if b.port == nil {
-		cfg.Port = defaultHTTPPort
-	} else {
-		if *b.port == 0 {
-			cfg.Port = randomPort()
-		} else if *b.port < 0 {
-			return Config{}, errors.New("port should be positive")
-		} else {
-			cfg.Port = *b.port
+func (b *ConfigBuilder) ReadTimeout(timeout time.Duration) *ConfigBuilder {
+	b.readTimeout = &timeout
+	return b
+}
+
+func (b *ConfigBuilder) WriteTimeout(timeout time.Duration) *ConfigBuilder {
+	b.writeTimeout = &timeout
+	return b
+}
+
+func (b *ConfigBuilder) IdleTimeout(timeout time.Duration) *ConfigBuilder {
+	b.idleTimeout = &timeout
+	return b
+}
+
+func (b *ConfigBuilder) MaxHeaderBytes(n int) *ConfigBuilder {
+	b.maxHeader = &n
+	return b
+}
+
+func (b *ConfigBuilder) TLS(certFile, keyFile string) *ConfigBuilder {
+	b.certFile = &certFile
+	b.keyFile = &keyFile
+	return b
+}
+
+func (b *ConfigBuilder) LogLevel(level string) *ConfigBuilder {
+	b.logLevel = &level
+	return b
+}
+
+func (b *ConfigBuilder) Build() (config.Config, error) {
+	cfg := config.Default()
+	if b.base != nil {
+		cfg = *b.base
+	}
+
+	if b.port != nil {
+		if *b.port < 0 {
+			return config.Config{}, errors.New("port should be positive")
+		}
+		cfg.Port = *b.port
+	}
+	if b.readTimeout != nil {
+		cfg.ReadTimeout = *b.readTimeout
+	}
+	if b.writeTimeout != nil {
+		cfg.WriteTimeout = *b.writeTimeout
+	}
+	if b.idleTimeout != nil {
+		cfg.IdleTimeout = *b.idleTimeout
+	}
+	if b.maxHeader != nil {
+		cfg.MaxHeaderBytes = *b.maxHeader
+	}
+	if b.certFile != nil {
+		cfg.TLSCertFile = *b.certFile
+		cfg.TLSKeyFile = *b.keyFile
+	}
+	if b.logLevel != nil {
+		if err := cfg.Set(config.KeyLogLevel, *b.logLevel); err != nil {
+			return config.Config{}, err
 		}
 	}
 
-	return cfg, nil
+	return cfg, cfg.Validate()
 }
 
-func NewServer(addr string, config Config) (*http.Server, error) {
-	return nil, nil
+func NewServer(addr string, cfg config.Config) (*config.Server, error) {
+	if addr != "" {
+		cfg.Host = addr
+	}
+	return cfg.NewServer()
 }
 
 func client() error {
@@ -58,7 +122,3 @@ func client() error {
 	_ = server
 	return nil
 }
-
-func randomPort() int {
-	return 4 // Chosen by fair dice roll, guaranteed to be random.
-}
@@ -0,0 +1,44 @@
+package builder
+
+import (
+	"testing"
+	"time"
+
+	"github.com/teivah/100-go-mistakes/src/02-code-project-organization/11-functional-options/config"
+)
+
+func TestBuild(t *testing.T) {
+	builder := ConfigBuilder{}
+	builder.
+		Port(9090).
+		ReadTimeout(time.Second).
+		WriteTimeout(2 * time.Second).
+		LogLevel("debug")
+	cfg, err := builder.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := config.Default()
+	expected.Port = 9090
+	expected.ReadTimeout = time.Second
+	expected.WriteTimeout = 2 * time.Second
+	expected.LogLevel = "debug"
+	if cfg != expected {
+		t.Errorf("got: %+v, expected: %+v", cfg, expected)
+	}
+}
+
+func TestBuild_Invalid(t *testing.T) {
+	builder := ConfigBuilder{}
+	builder.Port(-1)
+	if _, err := builder.Build(); err == nil {
+		t.Error("expected an error")
+	}
+
+	builder = ConfigBuilder{}
+	builder.LogLevel("verbose")
+	if _, err := builder.Build(); err == nil {
+		t.Error("expected an error")
+	}
+}
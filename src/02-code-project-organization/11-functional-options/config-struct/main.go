@@ -1,12 +1,70 @@
-package main
+package configstruct
 
+import (
+	"time"
+
+	"github.com/teivah/100-go-mistakes/src/02-code-project-organization/11-functional-options/config"
+)
+
+// Config fields left to their zero value keep the default; hence, a port
+// set to 0 can't be told apart from an unset one.
 type Config struct {
-	Port int
+	Port           int
+	ReadTimeout    time.Duration
+	WriteTimeout   time.Duration
+	IdleTimeout    time.Duration
+	MaxHeaderBytes int
+	TLSCertFile    string
+	TLSKeyFile     string
+	LogLevel       string
 }
 
-func NewServer(addr string, cfg Config) {
+func (c Config) resolve(addr string) (config.Config, error) {
+	cfg := config.Default()
+	if addr != "" {
+		cfg.Host = addr
+	}
+	if c.Port != 0 {
+		cfg.Port = c.Port
+	}
+	if c.ReadTimeout != 0 {
+		cfg.ReadTimeout = c.ReadTimeout
+	}
+	if c.WriteTimeout != 0 {
+		cfg.WriteTimeout = c.WriteTimeout
+	}
+	if c.IdleTimeout != 0 {
+		cfg.IdleTimeout = c.IdleTimeout
+	}
+	if c.MaxHeaderBytes != 0 {
+		cfg.MaxHeaderBytes = c.MaxHeaderBytes
+	}
+	if c.TLSCertFile != "" || c.TLSKeyFile != "" {
+		cfg.TLSCertFile = c.TLSCertFile
+		cfg.TLSKeyFile = c.TLSKeyFile
+	}
+	if c.LogLevel != "" {
+		if err := cfg.Set(config.KeyLogLevel, c.LogLevel); err != nil {
+			return config.Config{}, err
+		}
+	}
+	return cfg, cfg.Validate()
 }
 
-func main() {
-	NewServer("localhost", Config{})
+func NewServer(addr string, c Config) (*config.Server, error) {
+	cfg, err := c.resolve(addr)
+	if err != nil {
+		return nil, err
+	}
+	return cfg.NewServer()
+}
+
+func client() error {
+	server, err := NewServer("localhost", Config{})
+	if err != nil {
+		return err
+	}
+
+	_ = server
+	return nil
 }
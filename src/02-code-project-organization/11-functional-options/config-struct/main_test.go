@@ -0,0 +1,39 @@
+package configstruct
+
+import (
+	"testing"
+	"time"
+
+	"github.com/teivah/100-go-mistakes/src/02-code-project-organization/11-functional-options/config"
+)
+
+func TestResolve(t *testing.T) {
+	cfg, err := Config{
+		Port:         9090,
+		ReadTimeout:  time.Second,
+		WriteTimeout: 2 * time.Second,
+		LogLevel:     "debug",
+	}.resolve("localhost")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := config.Default()
+	expected.Port = 9090
+	expected.ReadTimeout = time.Second
+	expected.WriteTimeout = 2 * time.Second
+	expected.LogLevel = "debug"
+	if cfg != expected {
+		t.Errorf("got: %+v, expected: %+v", cfg, expected)
+	}
+}
+
+func TestResolve_ZeroValue(t *testing.T) {
+	cfg, err := Config{}.resolve("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg != config.Default() {
+		t.Errorf("got: %+v", cfg)
+	}
+}
@@ -0,0 +1,159 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultHost = "localhost"
+	DefaultPort = 8080
+)
+
+// Config is the server configuration produced by the builder, config-struct
+// and functional-options front-ends.
+type Config struct {
+	Host           string
+	Port           int // 0 means an OS-assigned port
+	ReadTimeout    time.Duration
+	WriteTimeout   time.Duration
+	IdleTimeout    time.Duration
+	MaxHeaderBytes int
+	TLSCertFile    string
+	TLSKeyFile     string
+	LogLevel       string
+}
+
+// Keys accepted by Set, LoadFile and LoadEnv.
+const (
+	KeyHost           = "host"
+	KeyPort           = "port"
+	KeyReadTimeout    = "read_timeout"
+	KeyWriteTimeout   = "write_timeout"
+	KeyIdleTimeout    = "idle_timeout"
+	KeyMaxHeaderBytes = "max_header_bytes"
+	KeyTLSCertFile    = "tls_cert_file"
+	KeyTLSKeyFile     = "tls_key_file"
+	KeyLogLevel       = "log_level"
+)
+
+var keys = []string{
+	KeyHost, KeyPort, KeyReadTimeout, KeyWriteTimeout, KeyIdleTimeout,
+	KeyMaxHeaderBytes, KeyTLSCertFile, KeyTLSKeyFile, KeyLogLevel,
+}
+
+var logLevels = map[string]bool{
+	"debug": true,
+	"info":  true,
+	"warn":  true,
+	"error": true,
+}
+
+// Default returns the bottom layer of every configuration.
+func Default() Config {
+	return Config{
+		Host:         DefaultHost,
+		Port:         DefaultPort,
+		ReadTimeout:  5 * time.Second,
+		WriteTimeout: 10 * time.Second,
+		IdleTimeout:  2 * time.Minute,
+		LogLevel:     "info",
+	}
+}
+
+// Load returns the default configuration overlaid with the file at path (if
+// path isn't empty) and then with the environment variables using prefix.
+func Load(path, prefix string) (Config, error) {
+	cfg := Default()
+	if path != "" {
+		if err := cfg.LoadFile(path); err != nil {
+			return Config{}, err
+		}
+	}
+	if err := cfg.LoadEnv(prefix); err != nil {
+		return Config{}, err
+	}
+	return cfg, cfg.Validate()
+}
+
+// Set parses value and assigns it to the field identified by key.
+func (c *Config) Set(key, value string) error {
+	var err error
+	switch key {
+	case KeyHost:
+		c.Host = value
+	case KeyPort:
+		c.Port, err = strconv.Atoi(value)
+	case KeyReadTimeout:
+		c.ReadTimeout, err = time.ParseDuration(value)
+	case KeyWriteTimeout:
+		c.WriteTimeout, err = time.ParseDuration(value)
+	case KeyIdleTimeout:
+		c.IdleTimeout, err = time.ParseDuration(value)
+	case KeyMaxHeaderBytes:
+		c.MaxHeaderBytes, err = strconv.Atoi(value)
+	case KeyTLSCertFile:
+		c.TLSCertFile = value
+	case KeyTLSKeyFile:
+		c.TLSKeyFile = value
+	case KeyLogLevel:
+		level := strings.ToLower(value)
+		if !logLevels[level] {
+			return fmt.Errorf("unknown log level %q", value)
+		}
+		c.LogLevel = level
+	default:
+		return fmt.Errorf("unknown key %q", key)
+	}
+	if err != nil {
+		return fmt.Errorf("invalid %s: %w", key, err)
+	}
+	return nil
+}
+
+// Validate returns an error listing every invalid field.
+func (c Config) Validate() error {
+	errs := c.Errors()
+	if len(errs) == 0 {
+		return nil
+	}
+	msgs := make([]string, len(errs))
+	for i, err := range errs {
+		msgs[i] = err.Error()
+	}
+	return errors.New("invalid config: " + strings.Join(msgs, "; "))
+}
+
+// Errors returns an error per invalid field, for callers gathering them
+// with other errors.
+func (c Config) Errors() []error {
+	var errs []error
+	if c.Host == "" {
+		errs = append(errs, errors.New("host should be set"))
+	}
+	if c.Port < 0 || c.Port > 65535 {
+		errs = append(errs, errors.New("port should be between 0 and 65535"))
+	}
+	if c.ReadTimeout < 0 || c.WriteTimeout < 0 || c.IdleTimeout < 0 {
+		errs = append(errs, errors.New("timeouts should be positive"))
+	}
+	if c.MaxHeaderBytes < 0 {
+		errs = append(errs, errors.New("max header bytes should be positive"))
+	}
+	if (c.TLSCertFile == "") != (c.TLSKeyFile == "") {
+		errs = append(errs, errors.New("both TLS certificate and key files should be set"))
+	}
+	if !logLevels[c.LogLevel] {
+		errs = append(errs, fmt.Errorf("unknown log level %q", c.LogLevel))
+	}
+	return errs
+}
+
+// Addr returns the host:port address to listen on.
+func (c Config) Addr() string {
+	return net.JoinHostPort(c.Host, strconv.Itoa(c.Port))
+}
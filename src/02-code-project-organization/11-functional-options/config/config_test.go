@@ -0,0 +1,137 @@
+package config
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadFile(t *testing.T) {
+	tests := map[string]struct {
+		name    string
+		content string
+	}{
+		`json`: {
+			name: "config.json",
+			content: `{
+				"host": "example.com",
+				"port": 9090,
+				"read_timeout": "1s",
+				"log_level": "debug"
+			}`,
+		},
+		`yaml`: {
+			name: "config.yaml",
+			content: `# server
+host: example.com
+port: 9090 # comment
+read_timeout: "1s"
+log_level: 'debug'
+`,
+		},
+	}
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			cfg := Default()
+			if err := cfg.LoadFile(writeFile(t, tt.name, tt.content)); err != nil {
+				t.Fatal(err)
+			}
+			expected := Default()
+			expected.Host = "example.com"
+			expected.Port = 9090
+			expected.ReadTimeout = time.Second
+			expected.LogLevel = "debug"
+			if cfg != expected {
+				t.Errorf("got: %+v, expected: %+v", cfg, expected)
+			}
+		})
+	}
+}
+
+func TestLoadFile_Invalid(t *testing.T) {
+	cfg := Default()
+	if err := cfg.LoadFile(writeFile(t, "config.json", `{"foo": "bar"}`)); err == nil {
+		t.Error("expected an unknown key error")
+	}
+	if err := cfg.LoadFile(writeFile(t, "config.yaml", `port: abc`)); err == nil {
+		t.Error("expected a parsing error")
+	}
+	if err := cfg.LoadFile(writeFile(t, "config.toml", ``)); err == nil {
+		t.Error("expected an extension error")
+	}
+}
+
+func TestLoad_Layers(t *testing.T) {
+	path := writeFile(t, "config.yml", "port: 9090\nlog_level: warn\n")
+	t.Setenv("APP_PORT", "9091")
+	t.Setenv("APP_TLS_CERT_FILE", "cert.pem")
+	t.Setenv("APP_TLS_KEY_FILE", "key.pem")
+
+	cfg, err := Load(path, "APP_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := Default()
+	expected.Port = 9091
+	expected.LogLevel = "warn"
+	expected.TLSCertFile = "cert.pem"
+	expected.TLSKeyFile = "key.pem"
+	if cfg != expected {
+		t.Errorf("got: %+v, expected: %+v", cfg, expected)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	cfg := Default()
+	cfg.Port = 70000
+	cfg.TLSCertFile = "cert.pem"
+	cfg.LogLevel = "verbose"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected an error")
+	}
+	if errs := cfg.Errors(); len(errs) != 3 {
+		t.Errorf("expected 3 errors, got: %v", errs)
+	}
+}
+
+func TestSet_LogLevel(t *testing.T) {
+	cfg := Default()
+	if err := cfg.Set(KeyLogLevel, "DEBUG"); err != nil || cfg.LogLevel != "debug" {
+		t.Errorf("got: %q, %v", cfg.LogLevel, err)
+	}
+	if err := cfg.Set(KeyLogLevel, "bogus"); err == nil || cfg.LogLevel != "debug" {
+		t.Errorf("expected an error and no change, got: %q, %v", cfg.LogLevel, err)
+	}
+}
+
+func TestServer_ShutdownWithoutServing(t *testing.T) {
+	cfg := Default()
+	cfg.Host = "127.0.0.1"
+	cfg.Port = 0
+	server, err := cfg.NewServer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := server.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	// The port held for the server is released
+	l, err := net.Listen("tcp", server.Addr)
+	if err != nil {
+		t.Fatalf("port still held: %v", err)
+	}
+	_ = l.Close()
+}
@@ -0,0 +1,103 @@
+package config_test
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/teivah/100-go-mistakes/src/02-code-project-organization/11-functional-options/builder"
+	"github.com/teivah/100-go-mistakes/src/02-code-project-organization/11-functional-options/config"
+	configstruct "github.com/teivah/100-go-mistakes/src/02-code-project-organization/11-functional-options/config-struct"
+	"github.com/teivah/100-go-mistakes/src/02-code-project-organization/11-functional-options/config/configtest"
+	functionaloptions "github.com/teivah/100-go-mistakes/src/02-code-project-organization/11-functional-options/functional-options"
+)
+
+// TestFrontEnds builds a server with the same settings through the three
+// front-ends and expects the same result.
+func TestFrontEnds(t *testing.T) {
+	certFile, keyFile := configtest.WriteCertificate(t)
+
+	var b builder.ConfigBuilder
+	cfg, err := b.Port(9443).
+		ReadTimeout(time.Second).
+		WriteTimeout(2*time.Second).
+		IdleTimeout(3*time.Second).
+		MaxHeaderBytes(4096).
+		TLS(certFile, keyFile).
+		LogLevel("debug").
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	fromBuilder, err := builder.NewServer("127.0.0.1", cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fromStruct, err := configstruct.NewServer("127.0.0.1", configstruct.Config{
+		Port:           9443,
+		ReadTimeout:    time.Second,
+		WriteTimeout:   2 * time.Second,
+		IdleTimeout:    3 * time.Second,
+		MaxHeaderBytes: 4096,
+		TLSCertFile:    certFile,
+		TLSKeyFile:     keyFile,
+		LogLevel:       "debug",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fromOptions, err := functionaloptions.NewServer("127.0.0.1",
+		functionaloptions.WithPort(9443),
+		functionaloptions.WithReadTimeout(time.Second),
+		functionaloptions.WithWriteTimeout(2*time.Second),
+		functionaloptions.WithIdleTimeout(3*time.Second),
+		functionaloptions.WithMaxHeaderBytes(4096),
+		functionaloptions.WithTLS(certFile, keyFile),
+		functionaloptions.WithLogLevel("debug"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := fromBuilder.Server
+	if expected.Addr != "127.0.0.1:9443" || expected.MaxHeaderBytes != 4096 || expected.TLSConfig == nil {
+		t.Fatalf("unexpected server: %+v", expected)
+	}
+	for name, server := range map[string]*config.Server{
+		"config struct":      fromStruct,
+		"functional options": fromOptions,
+	} {
+		if !sameServer(server.Server, expected) {
+			t.Errorf("%s: got %+v, expected %+v", name, server.Server, expected)
+		}
+	}
+}
+
+func sameServer(a, b *http.Server) bool {
+	if a.Addr != b.Addr ||
+		a.ReadTimeout != b.ReadTimeout ||
+		a.WriteTimeout != b.WriteTimeout ||
+		a.IdleTimeout != b.IdleTimeout ||
+		a.MaxHeaderBytes != b.MaxHeaderBytes {
+		return false
+	}
+	if (a.TLSConfig == nil) != (b.TLSConfig == nil) {
+		return false
+	}
+	if a.TLSConfig == nil {
+		return true
+	}
+	if a.TLSConfig.MinVersion != b.TLSConfig.MinVersion ||
+		len(a.TLSConfig.Certificates) != len(b.TLSConfig.Certificates) {
+		return false
+	}
+	for i, cert := range a.TLSConfig.Certificates {
+		if !bytes.Equal(cert.Certificate[0], b.TLSConfig.Certificates[i].Certificate[0]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,113 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// LoadFile overlays the keys found in a JSON (.json) or YAML (.yaml, .yml)
+// file. Only the keys present in the file are changed.
+func (c *Config) LoadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading config file: %w", err)
+	}
+
+	var values map[string]string
+	switch ext := filepath.Ext(path); ext {
+	case ".json":
+		values, err = parseJSON(data)
+	case ".yaml", ".yml":
+		values, err = parseYAML(data)
+	default:
+		return fmt.Errorf("unsupported config file extension %q", ext)
+	}
+	if err != nil {
+		return fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return c.setAll(values)
+}
+
+// LoadEnv overlays the environment variables named after the upper-cased keys
+// and prefixed with prefix (e.g. APP_PORT for the prefix APP_).
+func (c *Config) LoadEnv(prefix string) error {
+	values := make(map[string]string)
+	for _, key := range keys {
+		if v, ok := os.LookupEnv(prefix + strings.ToUpper(key)); ok {
+			values[key] = v
+		}
+	}
+	return c.setAll(values)
+}
+
+func (c *Config) setAll(values map[string]string) error {
+	// Iterate over keys rather than values for a deterministic error
+	for _, key := range keys {
+		v, ok := values[key]
+		if !ok {
+			continue
+		}
+		if err := c.Set(key, v); err != nil {
+			return err
+		}
+		delete(values, key)
+	}
+	for key := range values {
+		return fmt.Errorf("unknown key %q", key)
+	}
+	return nil
+}
+
+func parseJSON(data []byte) (map[string]string, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	values := make(map[string]string, len(raw))
+	for k, v := range raw {
+		var s string
+		if err := json.Unmarshal(v, &s); err == nil {
+			values[k] = s
+			continue
+		}
+		// Numbers are kept as written, e.g. "port": 8080
+		values[k] = string(bytes.TrimSpace(v))
+	}
+	return values, nil
+}
+
+// parseYAML handles the subset of YAML used by flat configuration files:
+// one "key: value" pair per line, comments and optional quotes.
+func parseYAML(data []byte) (map[string]string, error) {
+	values := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") || text == "---" {
+			continue
+		}
+		key, value, ok := strings.Cut(text, ":")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected key: value", line)
+		}
+		value = strings.TrimSpace(value)
+		if i := strings.Index(value, " #"); i >= 0 {
+			value = strings.TrimSpace(value[:i])
+		}
+		if unquoted, err := strconv.Unquote(value); err == nil {
+			value = unquoted
+		} else if len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'' {
+			value = value[1 : len(value)-1]
+		}
+		values[strings.TrimSpace(key)] = value
+	}
+	return values, scanner.Err()
+}
@@ -0,0 +1,85 @@
+package config
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+)
+
+// Server is an http.Server built from a Config. When the port is assigned by
+// the OS, the listener is kept open until ListenAndServe, so that no other
+// process can take the port in the meantime; Close or Shutdown release it
+// if the server never serves.
+type Server struct {
+	*http.Server
+	listener net.Listener
+}
+
+// NewServer builds a server from c, which should be valid. A zero port is
+// resolved and Addr holds the actual address.
+func (c Config) NewServer() (*Server, error) {
+	server := &Server{Server: &http.Server{
+		Addr:           c.Addr(),
+		ReadTimeout:    c.ReadTimeout,
+		WriteTimeout:   c.WriteTimeout,
+		IdleTimeout:    c.IdleTimeout,
+		MaxHeaderBytes: c.MaxHeaderBytes,
+	}}
+
+	if c.TLSCertFile != "" {
+		cert, err := tls.LoadX509KeyPair(c.TLSCertFile, c.TLSKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("loading TLS key pair: %w", err)
+		}
+		server.TLSConfig = &tls.Config{
+			Certificates: []tls.Certificate{cert},
+			MinVersion:   tls.VersionTLS12,
+		}
+	}
+
+	if c.Port == 0 {
+		l, err := net.Listen("tcp", c.Addr())
+		if err != nil {
+			return nil, fmt.Errorf("getting a random port: %w", err)
+		}
+		server.listener = l
+		server.Addr = l.Addr().String()
+	}
+	return server, nil
+}
+
+// ListenAndServe serves on the kept listener, if any, or else listens on
+// Addr. Contrary to http.Server.ListenAndServe, it serves TLS if the
+// configuration has a certificate.
+func (s *Server) ListenAndServe() error {
+	l := s.listener
+	if l == nil {
+		var err error
+		if l, err = net.Listen("tcp", s.Addr); err != nil {
+			return err
+		}
+	}
+	if s.TLSConfig != nil {
+		return s.Server.ServeTLS(l, "", "")
+	}
+	return s.Server.Serve(l)
+}
+
+// Close closes the kept listener as well as the server.
+func (s *Server) Close() error {
+	if s.listener != nil {
+		_ = s.listener.Close()
+	}
+	return s.Server.Close()
+}
+
+// Shutdown closes the kept listener, which http.Server.Shutdown doesn't know
+// about before serving, and shuts the server down gracefully.
+func (s *Server) Shutdown(ctx context.Context) error {
+	if s.listener != nil {
+		_ = s.listener.Close()
+	}
+	return s.Server.Shutdown(ctx)
+}
@@ -1,58 +1,189 @@
-package main
+package functionaloptions
 
 import (
 	"errors"
 	"net/http"
-)
+	"strings"
+	"time"
 
-const defaultHTTPPort = 8080
+	"github.com/teivah/100-go-mistakes/src/02-code-project-organization/11-functional-options/config"
+)
 
 type options struct {
-	port *int
+	config     config.Config
+	handler    http.Handler
+	onShutdown []func()
+	// applied counts the options applied so far
+	applied int
 }
 
 type Option func(options *options) error
 
+// OptionErrors gathers every error returned by the options passed to
+// NewServer, so that a caller can fix all of them at once.
+type OptionErrors []error
+
+func (e OptionErrors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, err := range e {
+		msgs = append(msgs, err.Error())
+	}
+	return "invalid options: " + strings.Join(msgs, "; ")
+}
+
+// WithConfig replaces the whole configuration, for example with the result
+// of config.Load. As it would discard the previous options, it must be the
+// first one; the addr given to NewServer still takes precedence over the
+// host.
+func WithConfig(cfg config.Config) Option {
+	return func(options *options) error {
+		if options.applied != 0 {
+			return errors.New("WithConfig should be the first option")
+		}
+		options.config = cfg
+		return nil
+	}
+}
+
 func WithPort(port int) Option {
 	return func(options *options) error {
 		if port < 0 {
 			return errors.New("port should be positive")
 		}
-		options.port = &port
+		options.config.Port = port
+		return nil
+	}
+}
+
+func WithHandler(handler http.Handler) Option {
+	return func(options *options) error {
+		if handler == nil {
+			return errors.New("handler should not be nil")
+		}
+		options.handler = handler
+		return nil
+	}
+}
+
+func WithTLS(certFile, keyFile string) Option {
+	return func(options *options) error {
+		if certFile == "" || keyFile == "" {
+			return errors.New("both certificate and key files should be set")
+		}
+		options.config.TLSCertFile = certFile
+		options.config.TLSKeyFile = keyFile
+		return nil
+	}
+}
+
+func WithReadTimeout(timeout time.Duration) Option {
+	return func(options *options) error {
+		if timeout < 0 {
+			return errors.New("read timeout should be positive")
+		}
+		options.config.ReadTimeout = timeout
+		return nil
+	}
+}
+
+func WithWriteTimeout(timeout time.Duration) Option {
+	return func(options *options) error {
+		if timeout < 0 {
+			return errors.New("write timeout should be positive")
+		}
+		options.config.WriteTimeout = timeout
+		return nil
+	}
+}
+
+func WithIdleTimeout(timeout time.Duration) Option {
+	return func(options *options) error {
+		if timeout < 0 {
+			return errors.New("idle timeout should be positive")
+		}
+		options.config.IdleTimeout = timeout
+		return nil
+	}
+}
+
+func WithMaxHeaderBytes(n int) Option {
+	return func(options *options) error {
+		if n < 0 {
+			return errors.New("max header bytes should be positive")
+		}
+		options.config.MaxHeaderBytes = n
+		return nil
+	}
+}
+
+func WithLogLevel(level string) Option {
+	return func(options *options) error {
+		return options.config.Set(config.KeyLogLevel, level)
+	}
+}
+
+// WithShutdownHook registers a function called when the server is shut down
+// gracefully using Shutdown.
+func WithShutdownHook(f func()) Option {
+	return func(options *options) error {
+		if f == nil {
+			return errors.New("shutdown hook should not be nil")
+		}
+		options.onShutdown = append(options.onShutdown, f)
 		return nil
 	}
 }
 
-func NewServer(addr string, opts ...Option) (*http.Server, error) {
-	var options options
+func newOptions(addr string, opts ...Option) (options, error) {
+	options := options{config: config.Default()}
+	var errs OptionErrors
 	for _, opt := range opts {
 		err := opt(&options)
 		if err != nil {
-			return nil, err
+			errs = append(errs, err)
 		}
+		options.applied++
+	}
+	if addr != "" {
+		options.config.Host = addr
+	}
+	// The config is validated even if an option failed, to report every
+	// error at once
+	errs = append(errs, options.config.Errors()...)
+	if len(errs) != 0 {
+		return options, errs
+	}
+	return options, nil
+}
+
+func NewServer(addr string, opts ...Option) (*config.Server, error) {
+	options, err := newOptions(addr, opts...)
+	if err != nil {
+		return nil, err
 	}
 
 	// At this stage, the options struct is built and contains the config
 	// Therefore, we can implement our logic related to port configuration
-	var port int
-	if options.port == nil {
-		port = defaultHTTPPort
-	} else {
-		if *options.port == 0 {
-			port = randomPort()
-		} else {
-			port = *options.port
-		}
+	server, err := options.config.NewServer()
+	if err != nil {
+		return nil, err
 	}
-
-	_ = port
-	return nil, nil
+	server.Handler = options.handler
+	for _, f := range options.onShutdown {
+		server.RegisterOnShutdown(f)
+	}
+	return server, nil
 }
 
-func client() {
-	_, _ = NewServer("localhost", WithPort(8080))
-}
+func client() error {
+	server, err := NewServer("localhost", WithPort(8080))
+	if err != nil {
+		return err
+	}
 
-func randomPort() int {
-	return 4 // Chosen by fair dice roll, guaranteed to be random.
+	// Serves TLS if configured with WithTLS
+	go func() {
+		_ = server.ListenAndServe()
+	}()
+	return nil
 }
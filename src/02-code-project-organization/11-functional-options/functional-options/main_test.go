@@ -0,0 +1,201 @@
+package functionaloptions
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/teivah/100-go-mistakes/src/02-code-project-organization/11-functional-options/config"
+	"github.com/teivah/100-go-mistakes/src/02-code-project-organization/11-functional-options/config/configtest"
+)
+
+func TestNewServer_Defaults(t *testing.T) {
+	server, err := NewServer("localhost")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if server.Addr != "localhost:8080" {
+		t.Errorf("got: %s", server.Addr)
+	}
+}
+
+func TestNewServer_Options(t *testing.T) {
+	server, err := NewServer("localhost",
+		WithPort(9090),
+		WithReadTimeout(time.Second),
+		WithWriteTimeout(2*time.Second),
+		WithIdleTimeout(3*time.Second),
+		WithMaxHeaderBytes(1024),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if server.Addr != "localhost:9090" {
+		t.Errorf("addr: got %s", server.Addr)
+	}
+	if server.ReadTimeout != time.Second ||
+		server.WriteTimeout != 2*time.Second ||
+		server.IdleTimeout != 3*time.Second {
+		t.Errorf("timeouts: got %v, %v, %v", server.ReadTimeout, server.WriteTimeout, server.IdleTimeout)
+	}
+	if server.MaxHeaderBytes != 1024 {
+		t.Errorf("max header bytes: got %d", server.MaxHeaderBytes)
+	}
+}
+
+func TestNewServer_AllErrors(t *testing.T) {
+	_, err := NewServer("localhost",
+		WithPort(-1),
+		WithReadTimeout(-time.Second),
+		WithTLS("", ""),
+	)
+	var errs OptionErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected OptionErrors, got: %v", err)
+	}
+	if len(errs) != 3 {
+		t.Errorf("expected 3 errors, got: %v", errs)
+	}
+}
+
+func TestNewServer_OptionAndConfigErrors(t *testing.T) {
+	tests := map[string][]Option{
+		"option errors": {WithPort(-1), WithLogLevel("bogus")},
+		"config error":  {WithConfig(config.Config{Port: 8080}), WithPort(-1)},
+	}
+	for name, opts := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewServer("localhost", opts...)
+			var errs OptionErrors
+			if !errors.As(err, &errs) {
+				t.Fatalf("expected OptionErrors, got: %v", err)
+			}
+			if len(errs) != 2 {
+				t.Errorf("expected 2 errors, got: %v", errs)
+			}
+		})
+	}
+}
+
+func TestNewServer_WithConfigFirst(t *testing.T) {
+	cfg := config.Default()
+	cfg.Port = 9090
+	if _, err := NewServer("localhost", WithConfig(cfg), WithReadTimeout(time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewServer("localhost", WithReadTimeout(time.Second), WithConfig(cfg)); err == nil {
+		t.Error("expected an error")
+	}
+}
+
+func TestNewServer_TLSMissingFiles(t *testing.T) {
+	_, err := NewServer("localhost", WithTLS("missing.crt", "missing.key"))
+	if err == nil {
+		t.Error("expected an error")
+	}
+}
+
+func TestNewServer_RandomPortServes(t *testing.T) {
+	shutdown := make(chan struct{})
+	server, err := NewServer("127.0.0.1",
+		WithPort(0),
+		WithHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = io.WriteString(w, "ok")
+		})),
+		WithShutdownHook(func() { close(shutdown) }),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, port, err := net.SplitHostPort(server.Addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p, _ := strconv.Atoi(port); p == 0 || p == config.DefaultPort {
+		t.Fatalf("expected an OS-assigned port, got: %s", port)
+	}
+
+	// The port is kept by the server until it serves
+	if l, err := net.Listen("tcp", server.Addr); err == nil {
+		_ = l.Close()
+		t.Fatal("the port isn't held")
+	}
+	go func() {
+		_ = server.ListenAndServe()
+	}()
+
+	resp, err := http.Get("http://" + server.Addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if string(body) != "ok" {
+		t.Errorf("got: %s", body)
+	}
+
+	if err := server.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-shutdown:
+	case <-time.After(time.Second):
+		t.Error("shutdown hook not called")
+	}
+}
+
+func TestNewOptions_Config(t *testing.T) {
+	options, err := newOptions("localhost",
+		WithPort(9090),
+		WithReadTimeout(time.Second),
+		WithWriteTimeout(2*time.Second),
+		WithLogLevel("debug"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := config.Default()
+	expected.Port = 9090
+	expected.ReadTimeout = time.Second
+	expected.WriteTimeout = 2 * time.Second
+	expected.LogLevel = "debug"
+	if options.config != expected {
+		t.Errorf("got: %+v, expected: %+v", options.config, expected)
+	}
+}
+
+func TestNewServer_ServesTLS(t *testing.T) {
+	certFile, keyFile := configtest.WriteCertificate(t)
+	server, err := NewServer("127.0.0.1",
+		WithPort(0),
+		WithTLS(certFile, keyFile),
+		WithHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = io.WriteString(w, "ok")
+		})),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		_ = server.ListenAndServe()
+	}()
+	defer server.Close()
+
+	client := &http.Client{Transport: &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	}}
+	resp, err := client.Get("https://" + server.Addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = resp.Body.Close()
+	if resp.TLS == nil {
+		t.Error("expected a TLS connection")
+	}
+}
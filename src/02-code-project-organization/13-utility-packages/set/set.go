@@ -0,0 +1,161 @@
+// Package set provides a generic set, which stringset specializes for strings.
+package set
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"sort"
+)
+
+// Set is a set of comparable values. A nil Set can be read but not written.
+type Set[T comparable] map[T]struct{}
+
+func New[T comparable](values ...T) Set[T] {
+	s := make(Set[T], len(values))
+	for _, v := range values {
+		s[v] = struct{}{}
+	}
+	return s
+}
+
+func (s Set[T]) Add(values ...T) {
+	for _, v := range values {
+		s[v] = struct{}{}
+	}
+}
+
+func (s Set[T]) Remove(values ...T) {
+	for _, v := range values {
+		delete(s, v)
+	}
+}
+
+func (s Set[T]) Contains(v T) bool {
+	_, ok := s[v]
+	return ok
+}
+
+func (s Set[T]) Len() int {
+	return len(s)
+}
+
+// Union returns a new set with the values of s or other.
+func (s Set[T]) Union(other Set[T]) Set[T] {
+	res := make(Set[T], len(s)+len(other))
+	for v := range s {
+		res[v] = struct{}{}
+	}
+	for v := range other {
+		res[v] = struct{}{}
+	}
+	return res
+}
+
+// Intersection returns a new set with the values of both s and other.
+func (s Set[T]) Intersection(other Set[T]) Set[T] {
+	small, large := s, other
+	if len(small) > len(large) {
+		small, large = large, small
+	}
+	res := make(Set[T])
+	for v := range small {
+		if large.Contains(v) {
+			res[v] = struct{}{}
+		}
+	}
+	return res
+}
+
+// Difference returns a new set with the values of s that aren't in other.
+func (s Set[T]) Difference(other Set[T]) Set[T] {
+	res := make(Set[T])
+	for v := range s {
+		if !other.Contains(v) {
+			res[v] = struct{}{}
+		}
+	}
+	return res
+}
+
+func (s Set[T]) Equal(other Set[T]) bool {
+	if len(s) != len(other) {
+		return false
+	}
+	for v := range s {
+		if !other.Contains(v) {
+			return false
+		}
+	}
+	return true
+}
+
+// Values returns the values in an unspecified order.
+func (s Set[T]) Values() []T {
+	values := make([]T, 0, len(s))
+	for v := range s {
+		values = append(values, v)
+	}
+	return values
+}
+
+// MarshalJSON encodes the set as a sorted array. The elements are sorted by
+// value if their kind is ordered (integers, floats, strings); otherwise, as
+// T isn't necessarily ordered, by their JSON encoding to keep the output
+// stable.
+func (s Set[T]) MarshalJSON() ([]byte, error) {
+	values := s.Values()
+	elems := make([]json.RawMessage, len(values))
+	for i, v := range values {
+		data, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		elems[i] = data
+	}
+
+	less, ok := valueLess(reflect.ValueOf(values))
+	if !ok {
+		less = func(i, j int) bool {
+			return bytes.Compare(elems[i], elems[j]) < 0
+		}
+	}
+	order := make([]int, len(values))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(i, j int) bool {
+		return less(order[i], order[j])
+	})
+
+	sorted := make([]json.RawMessage, len(elems))
+	for i, k := range order {
+		sorted[i] = elems[k]
+	}
+	return json.Marshal(sorted)
+}
+
+// valueLess compares the elements of a slice by index, if their kind is
+// ordered.
+func valueLess(v reflect.Value) (func(i, j int) bool, bool) {
+	switch v.Type().Elem().Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return func(i, j int) bool { return v.Index(i).Int() < v.Index(j).Int() }, true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return func(i, j int) bool { return v.Index(i).Uint() < v.Index(j).Uint() }, true
+	case reflect.Float32, reflect.Float64:
+		return func(i, j int) bool { return v.Index(i).Float() < v.Index(j).Float() }, true
+	case reflect.String:
+		return func(i, j int) bool { return v.Index(i).String() < v.Index(j).String() }, true
+	}
+	return nil, false
+}
+
+func (s *Set[T]) UnmarshalJSON(data []byte) error {
+	var values []T
+	if err := json.Unmarshal(data, &values); err != nil {
+		return err
+	}
+	*s = New(values...)
+	return nil
+}
@@ -0,0 +1,101 @@
+package set
+
+import (
+	"encoding/json"
+	"sort"
+	"testing"
+	"testing/quick"
+)
+
+func TestSet(t *testing.T) {
+	s := New(1, 2, 3)
+	s.Remove(2)
+	s.Add(4)
+	if !s.Equal(New(1, 3, 4)) {
+		t.Errorf("got: %v", s.Values())
+	}
+	if !s.Union(New(5)).Equal(New(1, 3, 4, 5)) {
+		t.Error("unexpected union")
+	}
+	if !s.Intersection(New(3, 5)).Equal(New(3)) {
+		t.Error("unexpected intersection")
+	}
+	if !s.Difference(New(3)).Equal(New(1, 4)) {
+		t.Error("unexpected difference")
+	}
+}
+
+func TestSet_JSON(t *testing.T) {
+	data, err := json.Marshal(New(3, 1, 2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `[1,2,3]` {
+		t.Errorf("got: %s", data)
+	}
+
+	type point struct{ X, Y int }
+	tests := []struct {
+		set      any
+		expected string
+	}{
+		{set: New(10, 2, 1, -5), expected: `[-5,1,2,10]`},
+		{set: New(uint8(200), 30), expected: `[30,200]`},
+		{set: New(1.5, 10.25, 2), expected: `[1.5,2,10.25]`},
+		{set: New("b", "a", "B"), expected: `["B","a","b"]`},
+		// Not ordered: sorted by encoding
+		{set: New(point{10, 0}, point{2, 0}), expected: `[{"X":10,"Y":0},{"X":2,"Y":0}]`},
+	}
+	for _, tt := range tests {
+		data, err := json.Marshal(tt.set)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != tt.expected {
+			t.Errorf("got: %s, expected: %s", data, tt.expected)
+		}
+	}
+}
+
+func TestSet_Properties(t *testing.T) {
+	properties := map[string]any{
+		"union is commutative": func(a, b []int) bool {
+			return New(a...).Union(New(b...)).Equal(New(b...).Union(New(a...)))
+		},
+		"intersection is commutative": func(a, b []int) bool {
+			return New(a...).Intersection(New(b...)).Equal(New(b...).Intersection(New(a...)))
+		},
+		"difference and intersection partition": func(a, b []int) bool {
+			sa, sb := New(a...), New(b...)
+			diff, inter := sa.Difference(sb), sa.Intersection(sb)
+			return diff.Intersection(inter).Len() == 0 && diff.Union(inter).Equal(sa)
+		},
+		"values are unique": func(a []int) bool {
+			values := New(a...).Values()
+			sort.Ints(values)
+			for i := 1; i < len(values); i++ {
+				if values[i-1] == values[i] {
+					return false
+				}
+			}
+			return true
+		},
+		"json round trip": func(a []string) bool {
+			s := New(a...)
+			data, err := json.Marshal(s)
+			if err != nil {
+				return false
+			}
+			var got Set[string]
+			return json.Unmarshal(data, &got) == nil && got.Equal(s)
+		},
+	}
+	for name, f := range properties {
+		f := f
+		t.Run(name, func(t *testing.T) {
+			if err := quick.Check(f, nil); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
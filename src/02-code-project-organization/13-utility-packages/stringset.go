@@ -1,7 +1,52 @@
 package stringset
 
-type Set map[string]struct{}
+import (
+	"sort"
 
-func New(...string) Set { return nil }
+	"github.com/teivah/100-go-mistakes/src/02-code-project-organization/13-utility-packages/set"
+)
 
-func (s Set) Sort() []string { return nil }
+// Set is a set of strings, a set.Set[string] with a Sort method. A nil Set
+// can be read but not written.
+type Set set.Set[string]
+
+func New(values ...string) Set {
+	return Set(set.New(values...))
+}
+
+func (s Set) Add(values ...string)    { set.Set[string](s).Add(values...) }
+func (s Set) Remove(values ...string) { set.Set[string](s).Remove(values...) }
+func (s Set) Contains(v string) bool  { return set.Set[string](s).Contains(v) }
+func (s Set) Len() int                { return len(s) }
+func (s Set) Equal(other Set) bool    { return set.Set[string](s).Equal(set.Set[string](other)) }
+
+// Union returns a new set with the values of s or other.
+func (s Set) Union(other Set) Set {
+	return Set(set.Set[string](s).Union(set.Set[string](other)))
+}
+
+// Intersection returns a new set with the values of both s and other.
+func (s Set) Intersection(other Set) Set {
+	return Set(set.Set[string](s).Intersection(set.Set[string](other)))
+}
+
+// Difference returns a new set with the values of s that aren't in other.
+func (s Set) Difference(other Set) Set {
+	return Set(set.Set[string](s).Difference(set.Set[string](other)))
+}
+
+// Sort returns the values in ascending order.
+func (s Set) Sort() []string {
+	values := set.Set[string](s).Values()
+	sort.Strings(values)
+	return values
+}
+
+// MarshalJSON encodes the set as a sorted array.
+func (s Set) MarshalJSON() ([]byte, error) {
+	return set.Set[string](s).MarshalJSON()
+}
+
+func (s *Set) UnmarshalJSON(data []byte) error {
+	return (*set.Set[string])(s).UnmarshalJSON(data)
+}
@@ -0,0 +1,90 @@
+package stringset
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"testing/quick"
+)
+
+func TestSet(t *testing.T) {
+	s := New("b", "a")
+	s.Add("c", "a")
+	s.Remove("b")
+	if !reflect.DeepEqual(s.Sort(), []string{"a", "c"}) {
+		t.Errorf("got: %v", s.Sort())
+	}
+	if !s.Contains("a") || s.Contains("b") {
+		t.Error("unexpected contains result")
+	}
+
+	other := New("c", "d")
+	if got := s.Union(other).Sort(); !reflect.DeepEqual(got, []string{"a", "c", "d"}) {
+		t.Errorf("union: %v", got)
+	}
+	if got := s.Intersection(other).Sort(); !reflect.DeepEqual(got, []string{"c"}) {
+		t.Errorf("intersection: %v", got)
+	}
+	if got := s.Difference(other).Sort(); !reflect.DeepEqual(got, []string{"a"}) {
+		t.Errorf("difference: %v", got)
+	}
+}
+
+func TestSet_JSON(t *testing.T) {
+	data, err := json.Marshal(struct{ Tags Set }{Tags: New("b", "c", "a")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"Tags":["a","b","c"]}` {
+		t.Errorf("got: %s", data)
+	}
+}
+
+func TestSet_Properties(t *testing.T) {
+	properties := map[string]any{
+		"union is commutative": func(a, b []string) bool {
+			return New(a...).Union(New(b...)).Equal(New(b...).Union(New(a...)))
+		},
+		"intersection is contained in both": func(a, b []string) bool {
+			sa, sb := New(a...), New(b...)
+			for v := range sa.Intersection(sb) {
+				if !sa.Contains(v) || !sb.Contains(v) {
+					return false
+				}
+			}
+			return true
+		},
+		"difference and intersection partition": func(a, b []string) bool {
+			sa, sb := New(a...), New(b...)
+			diff, inter := sa.Difference(sb), sa.Intersection(sb)
+			return diff.Intersection(inter).Len() == 0 && diff.Union(inter).Equal(sa)
+		},
+		"sort is sorted and complete": func(a []string) bool {
+			s := New(a...)
+			sorted := s.Sort()
+			for i := 1; i < len(sorted); i++ {
+				if sorted[i-1] >= sorted[i] {
+					return false
+				}
+			}
+			return New(sorted...).Equal(s)
+		},
+		"json round trip": func(a []string) bool {
+			s := New(a...)
+			data, err := json.Marshal(s)
+			if err != nil {
+				return false
+			}
+			var got Set
+			return json.Unmarshal(data, &got) == nil && got.Equal(s)
+		},
+	}
+	for name, f := range properties {
+		f := f
+		t.Run(name, func(t *testing.T) {
+			if err := quick.Check(f, nil); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+// Package bootstrap starts the components of an application explicitly, in
+// a declared order, instead of relying on init functions.
+package bootstrap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Component is a dependency of the application. Only Name and Start are
+// mandatory.
+type Component struct {
+	Name string
+	// Start creates the component, for example by opening a connection.
+	Start func(ctx context.Context) error
+	// Ready reports whether the component can serve requests. It's retried
+	// until it succeeds or the App readiness policy is exhausted.
+	Ready func(ctx context.Context) error
+	// Stop releases the component.
+	Stop func(ctx context.Context) error
+}
+
+// App starts components in their registration order and stops them in the
+// reverse order.
+type App struct {
+	// ReadyTimeout bounds each readiness check.
+	ReadyTimeout time.Duration
+	// ReadyRetries is the number of checks performed before giving up.
+	ReadyRetries int
+	// ReadyInterval is the delay between two checks.
+	ReadyInterval time.Duration
+
+	components []Component
+	started    []Component
+}
+
+func New() *App {
+	return &App{
+		ReadyTimeout:  time.Second,
+		ReadyRetries:  5,
+		ReadyInterval: 200 * time.Millisecond,
+	}
+}
+
+// Register appends c to the components to start.
+func (a *App) Register(c Component) *App {
+	a.components = append(a.components, c)
+	return a
+}
+
+// Start starts each component and waits for it to be ready before moving to
+// the next one. If a component fails, the ones already started are stopped.
+func (a *App) Start(ctx context.Context) error {
+	for _, c := range a.components {
+		if err := a.start(ctx, c); err != nil {
+			if stopErr := a.Stop(ctx); stopErr != nil {
+				return fmt.Errorf("%w (rollback: %v)", err, stopErr)
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+func (a *App) start(ctx context.Context, c Component) error {
+	if c.Start == nil {
+		return fmt.Errorf("component %s: missing Start", c.Name)
+	}
+	if err := c.Start(ctx); err != nil {
+		return fmt.Errorf("starting %s: %w", c.Name, err)
+	}
+	// Once started, a component has to be stopped even if it's not ready
+	a.started = append(a.started, c)
+	if c.Ready == nil {
+		return nil
+	}
+	if err := a.waitReady(ctx, c); err != nil {
+		return fmt.Errorf("%s not ready: %w", c.Name, err)
+	}
+	return nil
+}
+
+func (a *App) waitReady(ctx context.Context, c Component) error {
+	retries := a.ReadyRetries
+	if retries < 1 {
+		retries = 1
+	}
+	var err error
+	for i := 0; i < retries; i++ {
+		if i > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(a.ReadyInterval):
+			}
+		}
+		err = a.check(ctx, c)
+		if err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+func (a *App) check(ctx context.Context, c Component) error {
+	if a.ReadyTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, a.ReadyTimeout)
+		defer cancel()
+	}
+	return c.Ready(ctx)
+}
+
+// Stop stops the started components in the reverse order. It stops all of
+// them even if some fail and returns the errors together.
+func (a *App) Stop(ctx context.Context) error {
+	var msgs []string
+	for i := len(a.started) - 1; i >= 0; i-- {
+		c := a.started[i]
+		if c.Stop == nil {
+			continue
+		}
+		if err := c.Stop(ctx); err != nil {
+			msgs = append(msgs, fmt.Sprintf("stopping %s: %v", c.Name, err))
+		}
+	}
+	a.started = nil
+	if len(msgs) != 0 {
+		return errors.New(strings.Join(msgs, "; "))
+	}
+	return nil
+}
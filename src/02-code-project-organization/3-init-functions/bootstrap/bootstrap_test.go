@@ -0,0 +1,117 @@
+package bootstrap
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type recorder struct {
+	events []string
+}
+
+func (r *recorder) component(name string, startErr error) Component {
+	return Component{
+		Name: name,
+		Start: func(ctx context.Context) error {
+			r.events = append(r.events, "start "+name)
+			return startErr
+		},
+		Stop: func(ctx context.Context) error {
+			r.events = append(r.events, "stop "+name)
+			return nil
+		},
+	}
+}
+
+func TestApp_Order(t *testing.T) {
+	var r recorder
+	app := New().
+		Register(r.component("db", nil)).
+		Register(r.component("redis", nil)).
+		Register(r.component("http", nil))
+
+	ctx := context.Background()
+	if err := app.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := app.Stop(ctx); err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{
+		"start db", "start redis", "start http",
+		"stop http", "stop redis", "stop db",
+	}
+	if !reflect.DeepEqual(r.events, expected) {
+		t.Errorf("got: %v, expected: %v", r.events, expected)
+	}
+}
+
+func TestApp_StartFailureRollsBack(t *testing.T) {
+	var r recorder
+	app := New().
+		Register(r.component("db", nil)).
+		Register(r.component("redis", errors.New("foo"))).
+		Register(r.component("http", nil))
+
+	if err := app.Start(context.Background()); err == nil {
+		t.Fatal("expected an error")
+	}
+	// A component failing to start isn't stopped
+	expected := []string{"start db", "start redis", "stop db"}
+	if !reflect.DeepEqual(r.events, expected) {
+		t.Errorf("got: %v, expected: %v", r.events, expected)
+	}
+}
+
+func TestApp_ReadinessRetries(t *testing.T) {
+	checks := 0
+	app := New()
+	app.ReadyInterval = time.Millisecond
+	app.Register(Component{
+		Name:  "db",
+		Start: func(ctx context.Context) error { return nil },
+		Ready: func(ctx context.Context) error {
+			checks++
+			if checks < 3 {
+				return errors.New("not yet")
+			}
+			return nil
+		},
+	})
+	if err := app.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if checks != 3 {
+		t.Errorf("checks: %d", checks)
+	}
+}
+
+func TestApp_ReadinessTimeout(t *testing.T) {
+	stopped := false
+	app := New()
+	app.ReadyTimeout = time.Millisecond
+	app.ReadyRetries = 2
+	app.ReadyInterval = time.Millisecond
+	app.Register(Component{
+		Name:  "db",
+		Start: func(ctx context.Context) error { return nil },
+		Ready: func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		},
+		Stop: func(ctx context.Context) error {
+			stopped = true
+			return nil
+		},
+	})
+	err := app.Start(context.Background())
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected a deadline error, got: %v", err)
+	}
+	if !stopped {
+		t.Error("expected the started component to be stopped")
+	}
+}
@@ -1,24 +1,44 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"log"
 	"os"
+
+	"github.com/teivah/100-go-mistakes/src/02-code-project-organization/3-init-functions/bootstrap"
 )
 
-var db *sql.DB
+func main() {
+	var db *sql.DB
+	app := bootstrap.New().Register(bootstrap.Component{
+		Name: "mysql",
+		Start: func(ctx context.Context) error {
+			d, err := createClient(os.Getenv("MYSQL_DATA_SOURCE_NAME"))
+			if err != nil {
+				return err
+			}
+			db = d
+			return nil
+		},
+		Ready: func(ctx context.Context) error {
+			return db.PingContext(ctx)
+		},
+		Stop: func(ctx context.Context) error {
+			return db.Close()
+		},
+	})
 
-func init() {
-	dataSourceName := os.Getenv("MYSQL_DATA_SOURCE_NAME")
-	d, err := sql.Open("mysql", dataSourceName)
-	if err != nil {
-		log.Panic(err)
-	}
-	err = d.Ping()
-	if err != nil {
-		log.Panic(err)
+	ctx := context.Background()
+	if err := app.Start(ctx); err != nil {
+		log.Fatal(err)
 	}
-	db = d
+	defer func() {
+		if err := app.Stop(ctx); err != nil {
+			log.Print(err)
+		}
+	}()
+	// Use db
 }
 
 func createClient(dataSourceName string) (*sql.DB, error) {
@@ -27,6 +47,7 @@ func createClient(dataSourceName string) (*sql.DB, error) {
 		return nil, err
 	}
 	if err = db.Ping(); err != nil {
+		_ = db.Close()
 		return nil, err
 	}
 	return db, nil
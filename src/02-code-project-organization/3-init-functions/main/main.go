@@ -1,8 +1,12 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"log"
+	"os"
 
+	"github.com/teivah/100-go-mistakes/src/02-code-project-organization/3-init-functions/bootstrap"
 	"github.com/teivah/100-go-mistakes/src/02-code-project-organization/3-init-functions/redis"
 )
 
@@ -15,6 +19,46 @@ func init() {
 }
 
 func main() {
-	err := redis.Store("foo", "bar")
+	addr := os.Getenv("REDIS_ADDR")
+	var client *redis.Client
+	app := bootstrap.New().Register(bootstrap.Component{
+		Name: "redis",
+		Start: func(ctx context.Context) error {
+			c, err := redis.Dial(ctx, addr)
+			if err != nil {
+				return err
+			}
+			client = c
+			return nil
+		},
+		Ready: func(ctx context.Context) error {
+			// A failed check, e.g. a timeout, leaves the client unusable:
+			// retrying with it would fail with ErrBrokenConn
+			if client.Broken() {
+				c, err := redis.Dial(ctx, addr)
+				if err != nil {
+					return err
+				}
+				_ = client.Close()
+				client = c
+			}
+			return client.Ping(ctx)
+		},
+		Stop: func(ctx context.Context) error {
+			return client.Close()
+		},
+	})
+
+	ctx := context.Background()
+	if err := app.Start(ctx); err != nil {
+		log.Fatal(err)
+	}
+	defer func() {
+		if err := app.Stop(ctx); err != nil {
+			log.Print(err)
+		}
+	}()
+
+	err := client.Set(ctx, "foo", "bar", 0)
 	_ = err
 }
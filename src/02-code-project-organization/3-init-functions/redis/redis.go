@@ -1,11 +1,249 @@
+// Package redis is a minimal Redis client speaking the RESP protocol.
 package redis
 
-import "fmt"
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
 
-func init() {
-	fmt.Println("redis")
+var (
+	// ErrNotFound is returned by Get when the key doesn't exist.
+	ErrNotFound = errors.New("redis: key not found")
+	// ErrBrokenConn is returned after an I/O or protocol error left the
+	// connection in an unknown state; a new client has to be dialed.
+	ErrBrokenConn = errors.New("redis: broken connection")
+)
+
+const (
+	// MaxBulkLength is the largest bulk string accepted, the Redis limit.
+	MaxBulkLength = 512 << 20
+	// MaxArrayLength is the largest number of command arguments accepted.
+	MaxArrayLength = 1 << 20
+)
+
+// Error is an error reply sent by the server.
+type Error string
+
+func (e Error) Error() string {
+	return "redis: " + string(e)
+}
+
+// Client is safe for concurrent use; the commands are serialized over a
+// single connection.
+type Client struct {
+	mu     sync.Mutex
+	conn   net.Conn
+	r      *bufio.Reader
+	broken error
+}
+
+// Dial connects to the server listening on addr.
+func Dial(ctx context.Context, addr string) (*Client, error) {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("redis: dialing %s: %w", addr, err)
+	}
+	return &Client{conn: conn, r: bufio.NewReader(conn)}, nil
+}
+
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// Broken reports whether an I/O or protocol error left the connection
+// unusable, in which case a new client has to be dialed.
+func (c *Client) Broken() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.broken != nil
+}
+
+func (c *Client) Ping(ctx context.Context) error {
+	_, err := c.do(ctx, "PING")
+	return err
+}
+
+// Get returns the value of key or ErrNotFound.
+func (c *Client) Get(ctx context.Context, key string) (string, error) {
+	reply, err := c.do(ctx, "GET", key)
+	if err != nil {
+		return "", err
+	}
+	if reply == nil {
+		return "", ErrNotFound
+	}
+	v, ok := reply.(string)
+	if !ok {
+		return "", fmt.Errorf("redis: unexpected GET reply %v", reply)
+	}
+	return v, nil
+}
+
+// Set stores value under key. A positive ttl makes the key expire.
+func (c *Client) Set(ctx context.Context, key, value string, ttl time.Duration) error {
+	args := []string{"SET", key, value}
+	if ttl > 0 {
+		args = append(args, "PX", strconv.FormatInt(ttl.Milliseconds(), 10))
+	}
+	_, err := c.do(ctx, args...)
+	return err
+}
+
+// Del deletes the keys and returns how many existed.
+func (c *Client) Del(ctx context.Context, keys ...string) (int64, error) {
+	reply, err := c.do(ctx, append([]string{"DEL"}, keys...)...)
+	if err != nil {
+		return 0, err
+	}
+	n, ok := reply.(int64)
+	if !ok {
+		return 0, fmt.Errorf("redis: unexpected DEL reply %v", reply)
+	}
+	return n, nil
+}
+
+func (c *Client) do(ctx context.Context, args ...string) (any, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.broken != nil {
+		return nil, fmt.Errorf("%w: %v", ErrBrokenConn, c.broken)
+	}
+
+	reply, err := c.roundTrip(ctx, args)
+	var redisErr Error
+	if err != nil && !errors.As(err, &redisErr) {
+		// The reply, if any, would be read by the next command
+		c.broken = err
+		_ = c.conn.Close()
+	}
+	return reply, err
+}
+
+func (c *Client) roundTrip(ctx context.Context, args []string) (any, error) {
+	// A zero deadline (no deadline in ctx) clears the previous one
+	deadline, _ := ctx.Deadline()
+	if err := c.conn.SetDeadline(deadline); err != nil {
+		return nil, err
+	}
+	if _, err := c.conn.Write(EncodeCommand(args...)); err != nil {
+		return nil, fmt.Errorf("redis: writing %s: %w", args[0], err)
+	}
+	return ReadReply(c.r)
+}
+
+// EncodeCommand encodes a command as a RESP array of bulk strings.
+func EncodeCommand(args ...string) []byte {
+	buf := []byte("*" + strconv.Itoa(len(args)) + "\r\n")
+	for _, arg := range args {
+		buf = append(buf, '$')
+		buf = strconv.AppendInt(buf, int64(len(arg)), 10)
+		buf = append(buf, "\r\n"...)
+		buf = append(buf, arg...)
+		buf = append(buf, "\r\n"...)
+	}
+	return buf
+}
+
+// ReadReply reads a simple string, error, integer or bulk string reply. A
+// null bulk string is returned as nil.
+func ReadReply(r *bufio.Reader) (any, error) {
+	line, err := readLine(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(line) == 0 {
+		return nil, errors.New("redis: empty reply")
+	}
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return nil, Error(line[1:])
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, fmt.Errorf("redis: invalid bulk length: %w", err)
+		}
+		if n == -1 {
+			return nil, nil
+		}
+		if n < 0 || n > MaxBulkLength {
+			return nil, fmt.Errorf("redis: invalid bulk length %d", n)
+		}
+		// Grows with the data actually received rather than the announced
+		// length
+		var buf strings.Builder
+		if _, err := io.CopyN(&buf, r, int64(n)+2); err != nil {
+			return nil, err
+		}
+		s := buf.String()
+		if s[n:] != "\r\n" {
+			return nil, fmt.Errorf("redis: bulk string of length %d not followed by CRLF", n)
+		}
+		return s[:n], nil
+	default:
+		return nil, fmt.Errorf("redis: unsupported reply %q", line)
+	}
+}
+
+// ReadCommand reads a command encoded by EncodeCommand.
+func ReadCommand(r *bufio.Reader) ([]string, error) {
+	line, err := readLine(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(line) == 0 || line[0] != '*' {
+		return nil, fmt.Errorf("redis: expected an array, got %q", line)
+	}
+	n, err := strconv.Atoi(line[1:])
+	if err != nil {
+		return nil, fmt.Errorf("redis: invalid array length: %w", err)
+	}
+	if n < 0 || n > MaxArrayLength {
+		return nil, fmt.Errorf("redis: invalid array length %d", n)
+	}
+	// The arguments are read one by one, so a large length alone doesn't
+	// allocate much
+	args := make([]string, 0, min(n, 16))
+	for i := 0; i < n; i++ {
+		reply, err := ReadReply(r)
+		if err != nil {
+			return nil, err
+		}
+		arg, ok := reply.(string)
+		if !ok {
+			return nil, fmt.Errorf("redis: expected a bulk string, got %v", reply)
+		}
+		args = append(args, arg)
+	}
+	return args, nil
+}
+
+func readLine(r *bufio.Reader) (string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	if len(line) < 2 || line[len(line)-2] != '\r' {
+		return "", fmt.Errorf("redis: malformed line %q", line)
+	}
+	return line[:len(line)-2], nil
 }
 
-func Store(key, value string) error {
-	return nil
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
 }
@@ -0,0 +1,171 @@
+package redis_test
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/teivah/100-go-mistakes/src/02-code-project-organization/3-init-functions/redis"
+	"github.com/teivah/100-go-mistakes/src/02-code-project-organization/3-init-functions/redis/redistest"
+)
+
+func newClient(t *testing.T) (*redis.Client, *redistest.Server) {
+	t.Helper()
+	server, err := redistest.NewServer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = server.Close() })
+
+	client, err := redis.Dial(context.Background(), server.Addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = client.Close() })
+	return client, server
+}
+
+func TestClient(t *testing.T) {
+	client, _ := newClient(t)
+	ctx := context.Background()
+
+	if err := client.Ping(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Set(ctx, "foo", "bar\r\nbaz", 0); err != nil {
+		t.Fatal(err)
+	}
+	got, err := client.Get(ctx, "foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "bar\r\nbaz" {
+		t.Errorf("got: %q", got)
+	}
+
+	n, err := client.Del(ctx, "foo", "missing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 {
+		t.Errorf("deleted: %d", n)
+	}
+	if _, err := client.Get(ctx, "foo"); !errors.Is(err, redis.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got: %v", err)
+	}
+}
+
+func TestClient_TTL(t *testing.T) {
+	client, server := newClient(t)
+	ctx := context.Background()
+
+	if err := client.Set(ctx, "foo", "bar", time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Get(ctx, "foo"); err != nil {
+		t.Fatal(err)
+	}
+	server.FastForward(time.Minute)
+	if _, err := client.Get(ctx, "foo"); !errors.Is(err, redis.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got: %v", err)
+	}
+}
+
+func TestClient_ServerError(t *testing.T) {
+	client, _ := newClient(t)
+	_, err := client.Del(context.Background())
+	var redisErr redis.Error
+	if !errors.As(err, &redisErr) {
+		t.Errorf("expected a redis.Error, got: %v", err)
+	}
+}
+
+func TestReadCommand_InvalidLengths(t *testing.T) {
+	inputs := []string{
+		"*-1\r\n",
+		"*2000000\r\n",
+		"*1\r\n$-2\r\n",
+		"*1\r\n$999999999999\r\n",
+		"*1\r\n$3\r\nfooXY",
+		"*1\r\n$3\r\nfoo\r\r",
+	}
+	for _, input := range inputs {
+		if _, err := redis.ReadCommand(bufio.NewReader(strings.NewReader(input))); err == nil {
+			t.Errorf("%q: expected an error", input)
+		}
+	}
+}
+
+// rawServer replies to each command with the given replies, in order.
+func rawServer(t *testing.T, replies ...string) string {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = l.Close() })
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		for _, reply := range replies {
+			if _, err := redis.ReadCommand(r); err != nil {
+				return
+			}
+			_, _ = io.WriteString(conn, reply)
+		}
+		_, _ = io.Copy(io.Discard, r)
+	}()
+	return l.Addr().String()
+}
+
+func TestClient_UnexpectedGetReply(t *testing.T) {
+	client, err := redis.Dial(context.Background(), rawServer(t, ":1\r\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	if _, err := client.Get(context.Background(), "foo"); err == nil {
+		t.Error("expected an error")
+	}
+}
+
+func TestClient_BrokenAfterTimeout(t *testing.T) {
+	// The server never replies to the first command
+	client, err := redis.Dial(context.Background(), rawServer(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := client.Ping(ctx); err == nil {
+		t.Fatal("expected a timeout")
+	}
+	if err := client.Ping(context.Background()); !errors.Is(err, redis.ErrBrokenConn) {
+		t.Errorf("expected ErrBrokenConn, got: %v", err)
+	}
+	if !client.Broken() {
+		t.Error("expected the client to be broken")
+	}
+}
+
+func TestClient_ServerErrorKeepsConn(t *testing.T) {
+	client, _ := newClient(t)
+	_, _ = client.Del(context.Background())
+	if err := client.Ping(context.Background()); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if client.Broken() {
+		t.Error("unexpected broken client")
+	}
+}
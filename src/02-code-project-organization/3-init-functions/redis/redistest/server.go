@@ -0,0 +1,194 @@
+// Package redistest provides an in-process Redis server for tests. It
+// supports PING, GET, SET (with EX and PX) and DEL.
+package redistest
+
+import (
+	"bufio"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/teivah/100-go-mistakes/src/02-code-project-organization/3-init-functions/redis"
+)
+
+type item struct {
+	value    string
+	expireAt time.Time // Zero if the key doesn't expire
+}
+
+// Server is a fake Redis server listening on a local port.
+type Server struct {
+	Addr string
+
+	listener net.Listener
+	wg       sync.WaitGroup
+
+	mu    sync.Mutex
+	items map[string]item
+	conns map[net.Conn]struct{}
+	// offset is added to the wall clock, see FastForward
+	offset time.Duration
+}
+
+// NewServer starts a server on a random port. It should be closed with
+// Close.
+func NewServer() (*Server, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	s := &Server{
+		Addr:     l.Addr().String(),
+		listener: l,
+		items:    make(map[string]item),
+		conns:    make(map[net.Conn]struct{}),
+	}
+	s.wg.Add(1)
+	go s.serve()
+	return s, nil
+}
+
+// Close stops the server and closes the client connections.
+func (s *Server) Close() error {
+	err := s.listener.Close()
+	s.mu.Lock()
+	for conn := range s.conns {
+		_ = conn.Close()
+	}
+	s.mu.Unlock()
+	s.wg.Wait()
+	return err
+}
+
+// FastForward moves the server clock forward so that the keys expire
+// without the tests having to sleep.
+func (s *Server) FastForward(d time.Duration) {
+	s.mu.Lock()
+	s.offset += d
+	s.mu.Unlock()
+}
+
+func (s *Server) now() time.Time {
+	return time.Now().Add(s.offset)
+}
+
+func (s *Server) serve() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		s.conns[conn] = struct{}{}
+		s.mu.Unlock()
+
+		s.wg.Add(1)
+		go s.handle(conn)
+	}
+}
+
+func (s *Server) handle(conn net.Conn) {
+	defer s.wg.Done()
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.mu.Unlock()
+		_ = conn.Close()
+	}()
+
+	r := bufio.NewReader(conn)
+	for {
+		args, err := redis.ReadCommand(r)
+		if err != nil {
+			return
+		}
+		if _, err := conn.Write(s.exec(args)); err != nil {
+			return
+		}
+	}
+}
+
+func (s *Server) exec(args []string) []byte {
+	if len(args) == 0 {
+		return errorReply("empty command")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch cmd := strings.ToUpper(args[0]); cmd {
+	case "PING":
+		return []byte("+PONG\r\n")
+	case "GET":
+		if len(args) != 2 {
+			return wrongArgs(cmd)
+		}
+		it, ok := s.get(args[1])
+		if !ok {
+			return []byte("$-1\r\n")
+		}
+		return []byte("$" + strconv.Itoa(len(it.value)) + "\r\n" + it.value + "\r\n")
+	case "SET":
+		return s.set(args)
+	case "DEL":
+		if len(args) < 2 {
+			return wrongArgs(cmd)
+		}
+		n := 0
+		for _, key := range args[1:] {
+			if _, ok := s.get(key); ok {
+				delete(s.items, key)
+				n++
+			}
+		}
+		return []byte(":" + strconv.Itoa(n) + "\r\n")
+	default:
+		return errorReply("unknown command '" + args[0] + "'")
+	}
+}
+
+func (s *Server) set(args []string) []byte {
+	if len(args) != 3 && len(args) != 5 {
+		return wrongArgs("SET")
+	}
+	it := item{value: args[2]}
+	if len(args) == 5 {
+		n, err := strconv.ParseInt(args[4], 10, 64)
+		if err != nil || n <= 0 {
+			return errorReply("invalid expire time")
+		}
+		switch strings.ToUpper(args[3]) {
+		case "EX":
+			it.expireAt = s.now().Add(time.Duration(n) * time.Second)
+		case "PX":
+			it.expireAt = s.now().Add(time.Duration(n) * time.Millisecond)
+		default:
+			return errorReply("syntax error")
+		}
+	}
+	s.items[args[1]] = it
+	return []byte("+OK\r\n")
+}
+
+// get returns the item of key, deleting it if expired. s.mu must be held.
+func (s *Server) get(key string) (item, bool) {
+	it, ok := s.items[key]
+	if !ok {
+		return item{}, false
+	}
+	if !it.expireAt.IsZero() && !s.now().Before(it.expireAt) {
+		delete(s.items, key)
+		return item{}, false
+	}
+	return it, true
+}
+
+func wrongArgs(cmd string) []byte {
+	return errorReply("wrong number of arguments for '" + strings.ToLower(cmd) + "' command")
+}
+
+func errorReply(msg string) []byte {
+	return []byte("-ERR " + msg + "\r\n")
+}
@@ -1,14 +1,124 @@
 package main
 
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// ErrCustomerExists is returned by a customerStorer when the ID is taken.
+var ErrCustomerExists = errors.New("customer exists")
+
 type customerStorer interface {
+	// StoreCustomer returns ErrCustomerExists if the ID is taken; the check
+	// belongs to the store so that it's atomic with the insertion.
 	StoreCustomer(Customer) error
 }
 
+type customerCreatedPublisher interface {
+	PublishCustomerCreated(CustomerCreated) error
+}
+
+// CustomerCreated is the event published once a customer is stored.
+type CustomerCreated struct {
+	ID        string
+	Email     string
+	CreatedAt time.Time
+}
+
+type ValidationError struct {
+	Field  string
+	Reason string
+}
+
+func (e ValidationError) Error() string {
+	return fmt.Sprintf("invalid %s: %s", e.Field, e.Reason)
+}
+
+type DuplicateCustomerError struct {
+	ID string
+}
+
+func (e DuplicateCustomerError) Error() string {
+	return fmt.Sprintf("customer %q already exists", e.ID)
+}
+
+// CustomerService2 is created with NewCustomerService2. The zero value of the
+// optional fields is usable: without a publisher, no event is published.
 type CustomerService2 struct {
-	storer customerStorer
+	storer    customerStorer
+	publisher customerCreatedPublisher
+	now       func() time.Time
+	logf      func(format string, args ...any)
+}
+
+func NewCustomerService2(storer customerStorer, publisher customerCreatedPublisher) CustomerService2 {
+	return CustomerService2{storer: storer, publisher: publisher, now: time.Now, logf: log.Printf}
+}
+
+var customerID = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,64}$`)
+
+// CreateNewCustomer stores a customer, then publishes its creation. Once the
+// customer is stored, the creation succeeded: a publishing failure is only
+// logged, as returning an error would make the caller retry and get a
+// duplicate.
+func (cs CustomerService2) CreateNewCustomer(id, email string) error {
+	customer := Customer{id: id, email: strings.TrimSpace(email)}
+	if err := customer.validate(); err != nil {
+		return err
+	}
+
+	if err := cs.storer.StoreCustomer(customer); err != nil {
+		if errors.Is(err, ErrCustomerExists) {
+			return DuplicateCustomerError{ID: id}
+		}
+		return fmt.Errorf("storing customer %s: %w", id, err)
+	}
+
+	if cs.publisher == nil {
+		return nil
+	}
+	now, logf := cs.now, cs.logf
+	if now == nil {
+		now = time.Now
+	}
+	if logf == nil {
+		logf = log.Printf
+	}
+	event := CustomerCreated{ID: id, Email: customer.email, CreatedAt: now()}
+	if err := cs.publisher.PublishCustomerCreated(event); err != nil {
+		logf("publishing creation of customer %s: %v", id, err)
+	}
+	return nil
+}
+
+func (c Customer) validate() error {
+	if !customerID.MatchString(c.id) {
+		return ValidationError{Field: "id", Reason: "should be 1 to 64 letters, digits, - or _"}
+	}
+	at := strings.IndexByte(c.email, '@')
+	if at <= 0 || at == len(c.email)-1 || strings.ContainsAny(c.email, " \t\r\n") {
+		return ValidationError{Field: "email", Reason: "malformed address"}
+	}
+	return nil
 }
 
-func (cs CustomerService2) CreateNewCustomer(id string) error {
-	customer := Customer{id: id}
-	return cs.storer.StoreCustomer(customer)
+// httpStatus maps the errors returned by CustomerService2 to a status code.
+func httpStatus(err error) int {
+	var validationErr ValidationError
+	var duplicateErr DuplicateCustomerError
+	switch {
+	case err == nil:
+		return http.StatusCreated
+	case errors.As(err, &validationErr):
+		return http.StatusBadRequest
+	case errors.As(err, &duplicateErr):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
 }
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+type fakeStorer struct {
+	customers map[string]Customer
+	err       error
+}
+
+func (s *fakeStorer) StoreCustomer(customer Customer) error {
+	if s.err != nil {
+		return s.err
+	}
+	if _, exists := s.customers[customer.id]; exists {
+		return fmt.Errorf("insert %s: %w", customer.id, ErrCustomerExists)
+	}
+	s.customers[customer.id] = customer
+	return nil
+}
+
+type fakePublisher struct {
+	events []CustomerCreated
+	err    error
+}
+
+func (p *fakePublisher) PublishCustomerCreated(event CustomerCreated) error {
+	if p.err != nil {
+		return p.err
+	}
+	p.events = append(p.events, event)
+	return nil
+}
+
+func newTestService() (CustomerService2, *fakeStorer, *fakePublisher) {
+	storer := &fakeStorer{customers: make(map[string]Customer)}
+	publisher := &fakePublisher{}
+	cs := NewCustomerService2(storer, publisher)
+	cs.now = func() time.Time { return time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC) }
+	cs.logf = func(string, ...any) {}
+	return cs, storer, publisher
+}
+
+func TestCreateNewCustomer(t *testing.T) {
+	cs, storer, publisher := newTestService()
+	if err := cs.CreateNewCustomer("c1", " foo@example.com "); err != nil {
+		t.Fatal(err)
+	}
+	if _, exists := storer.customers["c1"]; !exists {
+		t.Error("customer not stored")
+	}
+	expected := CustomerCreated{
+		ID:        "c1",
+		Email:     "foo@example.com",
+		CreatedAt: time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	if len(publisher.events) != 1 || publisher.events[0] != expected {
+		t.Errorf("got: %v", publisher.events)
+	}
+}
+
+func TestCreateNewCustomer_Errors(t *testing.T) {
+	tests := map[string]struct {
+		id       string
+		email    string
+		storeErr error
+		status   int
+	}{
+		`empty id`: {
+			id:     "",
+			email:  "foo@example.com",
+			status: http.StatusBadRequest,
+		},
+		`invalid email`: {
+			id:     "c2",
+			email:  "foo@",
+			status: http.StatusBadRequest,
+		},
+		`duplicate`: {
+			id:     "c1",
+			email:  "foo@example.com",
+			status: http.StatusConflict,
+		},
+		`storage failure`: {
+			id:       "c2",
+			email:    "foo@example.com",
+			storeErr: errors.New("foo"),
+			status:   http.StatusInternalServerError,
+		},
+	}
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			cs, storer, publisher := newTestService()
+			storer.customers["c1"] = Customer{id: "c1"}
+			storer.err = tt.storeErr
+
+			err := cs.CreateNewCustomer(tt.id, tt.email)
+			if got := httpStatus(err); got != tt.status {
+				t.Errorf("got status %d, expected %d (err: %v)", got, tt.status, err)
+			}
+			if len(publisher.events) != 0 {
+				t.Errorf("unexpected events: %v", publisher.events)
+			}
+		})
+	}
+}
+
+func TestCreateNewCustomer_PublishFailure(t *testing.T) {
+	cs, storer, publisher := newTestService()
+	publisher.err = errors.New("foo")
+	var logged []string
+	cs.logf = func(format string, args ...any) {
+		logged = append(logged, fmt.Sprintf(format, args...))
+	}
+
+	if err := cs.CreateNewCustomer("c1", "foo@example.com"); err != nil {
+		t.Fatalf("the customer is stored, expected no error: %v", err)
+	}
+	if _, exists := storer.customers["c1"]; !exists {
+		t.Error("customer not stored")
+	}
+	if len(logged) != 1 {
+		t.Errorf("expected the failure to be logged, got: %v", logged)
+	}
+}
+
+func TestCustomerService_Validation(t *testing.T) {
+	cs := CustomerService{}
+	var validationErr ValidationError
+	if err := cs.CreateNewCustomer("", "foo@example.com"); !errors.As(err, &validationErr) {
+		t.Errorf("expected a validation error, got: %v", err)
+	}
+	if err := cs.CreateNewCustomer("c1", "foo@example.com"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestCustomerService2_ZeroValue(t *testing.T) {
+	storer := &fakeStorer{customers: make(map[string]Customer)}
+	cs := CustomerService2{storer: storer}
+	if err := cs.CreateNewCustomer("c1", "foo@example.com"); err != nil {
+		t.Fatal(err)
+	}
+	publisher := &fakePublisher{}
+	cs = CustomerService2{storer: storer, publisher: publisher}
+	if err := cs.CreateNewCustomer("c2", "foo@example.com"); err != nil {
+		t.Fatal(err)
+	}
+	if len(publisher.events) != 1 || publisher.events[0].CreatedAt.IsZero() {
+		t.Errorf("got: %v", publisher.events)
+	}
+}
@@ -1,16 +1,22 @@
 package main
 
+import "strings"
+
 type CustomerService struct {
 	store Store
 }
 
-func (cs CustomerService) CreateNewCustomer(id string) error {
-	customer := Customer{id: id}
+func (cs CustomerService) CreateNewCustomer(id, email string) error {
+	customer := Customer{id: id, email: strings.TrimSpace(email)}
+	if err := customer.validate(); err != nil {
+		return err
+	}
 	return cs.store.StoreCustomer(customer)
 }
 
 type Customer struct {
-	id string
+	id    string
+	email string
 }
 
 type Store struct{}
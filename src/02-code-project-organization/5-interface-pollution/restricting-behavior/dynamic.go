@@ -0,0 +1,234 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Value is a configuration value safe for concurrent use. Set runs the
+// validators and then notifies the subscribers.
+type Value[T comparable] struct {
+	parse func(string) (T, error)
+
+	// notifyMu serializes the changes with their notifications, so that the
+	// subscribers observe them in order.
+	notifyMu    sync.Mutex
+	mu          sync.RWMutex
+	value       T
+	validators  []func(T) error
+	subscribers []func(old, new T)
+}
+
+type (
+	IntConfig      = Value[int]
+	StringConfig   = Value[string]
+	DurationConfig = Value[time.Duration]
+	BoolConfig     = Value[bool]
+)
+
+func NewIntConfig(v int, validators ...func(int) error) *IntConfig {
+	return newValue(v, strconv.Atoi, validators)
+}
+
+func NewStringConfig(v string, validators ...func(string) error) *StringConfig {
+	return newValue(v, func(s string) (string, error) { return s, nil }, validators)
+}
+
+func NewDurationConfig(v time.Duration, validators ...func(time.Duration) error) *DurationConfig {
+	return newValue(v, time.ParseDuration, validators)
+}
+
+func NewBoolConfig(v bool, validators ...func(bool) error) *BoolConfig {
+	return newValue(v, strconv.ParseBool, validators)
+}
+
+func newValue[T comparable](v T, parse func(string) (T, error), validators []func(T) error) *Value[T] {
+	return &Value[T]{parse: parse, value: v, validators: validators}
+}
+
+func (c *Value[T]) Get() T {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.value
+}
+
+// Set validates and stores v. The subscribers are called synchronously, in
+// the order of the changes, if the value changed; they can Get the value but
+// mustn't Set it.
+func (c *Value[T]) Set(v T) error {
+	if err := c.validate(v); err != nil {
+		return err
+	}
+	c.set(v)
+	return nil
+}
+
+func (c *Value[T]) validate(v T) error {
+	for _, validate := range c.validators {
+		if err := validate(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Value[T]) set(v T) {
+	c.notifyMu.Lock()
+	defer c.notifyMu.Unlock()
+
+	c.mu.Lock()
+	old := c.value
+	c.value = v
+	subscribers := c.subscribers
+	c.mu.Unlock()
+
+	if old == v {
+		return
+	}
+	for _, f := range subscribers {
+		f(old, v)
+	}
+}
+
+// Subscribe registers f to be called each time the value changes.
+func (c *Value[T]) Subscribe(f func(old, new T)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	// Copy so that set can iterate over its own slice without the lock
+	subscribers := make([]func(old, new T), len(c.subscribers), len(c.subscribers)+1)
+	copy(subscribers, c.subscribers)
+	c.subscribers = append(subscribers, f)
+}
+
+// prepare parses and validates raw, and returns a function applying it.
+func (c *Value[T]) prepare(raw string) (func(), error) {
+	v, err := c.parse(raw)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.validate(v); err != nil {
+		return nil, err
+	}
+	return func() { c.set(v) }, nil
+}
+
+type reloadable interface {
+	prepare(raw string) (func(), error)
+}
+
+// Registry names the values that can be reloaded from a file.
+type Registry struct {
+	mu     sync.Mutex
+	values map[string]reloadable
+}
+
+func NewRegistry() *Registry {
+	return &Registry{values: make(map[string]reloadable)}
+}
+
+// Register binds key to a value created with NewIntConfig, NewStringConfig,
+// NewDurationConfig or NewBoolConfig.
+func (r *Registry) Register(key string, value reloadable) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.values[key] = value
+}
+
+// Load applies "key = value" lines. If any line is invalid, none of them
+// is applied.
+func (r *Registry) Load(data []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	raw := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(text, "=")
+		if !ok {
+			return fmt.Errorf("line %d: expected key = value", line)
+		}
+		raw[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(raw))
+	for key := range raw {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	commits := make([]func(), 0, len(keys))
+	for _, key := range keys {
+		value, exists := r.values[key]
+		if !exists {
+			return fmt.Errorf("unknown key %q", key)
+		}
+		commit, err := value.prepare(raw[key])
+		if err != nil {
+			return fmt.Errorf("invalid %s: %w", key, err)
+		}
+		commits = append(commits, commit)
+	}
+	for _, commit := range commits {
+		commit()
+	}
+	return nil
+}
+
+func (r *Registry) LoadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return r.Load(data)
+}
+
+// Watch reloads the file at path each time its content changes, checking
+// every interval until ctx is canceled. The content is compared by hash, as
+// the modification time and size can stay the same across a rewrite. Reload
+// errors are passed to onError, if not nil, and the previous values are kept.
+func (r *Registry) Watch(ctx context.Context, path string, interval time.Duration, onError func(error)) {
+	if onError == nil {
+		onError = func(error) {}
+	}
+	var (
+		lastSum [sha256.Size]byte
+		loaded  bool
+	)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			onError(err)
+		} else if sum := sha256.Sum256(data); !loaded || sum != lastSum {
+			lastSum, loaded = sum, true
+			if err := r.Load(data); err != nil {
+				onError(err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
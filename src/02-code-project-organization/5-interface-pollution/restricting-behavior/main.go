@@ -1,35 +1,59 @@
 package main
 
-type IntConfig struct {
-	value int
-}
-
-func (c *IntConfig) Get() int {
-	return c.value
-}
-
-func (c *IntConfig) Set(value int) {
-	c.value = value
-}
+import (
+	"errors"
+	"sync/atomic"
+)
 
 type intConfigGetter interface {
 	Get() int
 }
 
+// intConfigWatcher can't update the value either.
+type intConfigWatcher interface {
+	intConfigGetter
+	Subscribe(func(old, new int))
+}
+
 type Foo struct {
 	threshold intConfigGetter
+	changes   *int64
 }
 
 func NewFoo(threshold intConfigGetter) Foo {
 	return Foo{threshold: threshold}
 }
 
+// NewWatchingFoo returns a Foo that is notified when the threshold changes.
+func NewWatchingFoo(threshold intConfigWatcher) Foo {
+	f := Foo{threshold: threshold, changes: new(int64)}
+	threshold.Subscribe(func(old, new int) {
+		atomic.AddInt64(f.changes, 1)
+	})
+	return f
+}
+
+// Changes returns how many times the threshold changed since NewWatchingFoo.
+func (f Foo) Changes() int64 {
+	if f.changes == nil {
+		return 0
+	}
+	return atomic.LoadInt64(f.changes)
+}
+
 func (f Foo) Bar() {
 	threshold := f.threshold.Get()
 	_ = threshold
 }
 
+func positive(v int) error {
+	if v <= 0 {
+		return errors.New("should be positive")
+	}
+	return nil
+}
+
 func main() {
-	foo := NewFoo(&IntConfig{value: 42})
+	foo := NewFoo(NewIntConfig(42, positive))
 	foo.Bar()
 }
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestValue(t *testing.T) {
+	threshold := NewIntConfig(42, positive)
+	foo := NewWatchingFoo(threshold)
+
+	if err := threshold.Set(-1); err == nil {
+		t.Error("expected a validation error")
+	}
+	if threshold.Get() != 42 {
+		t.Errorf("got: %d", threshold.Get())
+	}
+	if err := threshold.Set(43); err != nil {
+		t.Fatal(err)
+	}
+	// Setting the same value isn't a change
+	_ = threshold.Set(43)
+	if foo.Changes() != 1 {
+		t.Errorf("changes: %d", foo.Changes())
+	}
+}
+
+func TestValue_Concurrent(t *testing.T) {
+	v := NewDurationConfig(time.Second)
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		i := i
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			_ = v.Set(time.Duration(i) * time.Second)
+		}()
+		go func() {
+			defer wg.Done()
+			_ = v.Get()
+		}()
+	}
+	wg.Wait()
+}
+
+func TestValue_OrderedNotifications(t *testing.T) {
+	v := NewIntConfig(0)
+	var (
+		mu   sync.Mutex
+		last int
+	)
+	v.Subscribe(func(old, new int) {
+		mu.Lock()
+		defer mu.Unlock()
+		if old != last {
+			t.Errorf("notified %d -> %d after a change to %d", old, new, last)
+		}
+		last = new
+	})
+
+	var wg sync.WaitGroup
+	for i := 1; i <= 100; i++ {
+		i := i
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = v.Set(i)
+		}()
+	}
+	wg.Wait()
+	if last != v.Get() {
+		t.Errorf("last notified %d, value is %d", last, v.Get())
+	}
+}
+
+func newTestRegistry() (*Registry, *IntConfig, *StringConfig, *DurationConfig, *BoolConfig) {
+	threshold := NewIntConfig(42, positive)
+	name := NewStringConfig("foo")
+	timeout := NewDurationConfig(time.Second)
+	enabled := NewBoolConfig(false)
+
+	r := NewRegistry()
+	r.Register("threshold", threshold)
+	r.Register("name", name)
+	r.Register("timeout", timeout)
+	r.Register("enabled", enabled)
+	return r, threshold, name, timeout, enabled
+}
+
+func TestRegistry_Load(t *testing.T) {
+	r, threshold, name, timeout, enabled := newTestRegistry()
+	err := r.Load([]byte(`
+# comment
+threshold = 10
+name = bar
+timeout = 5s
+enabled = true
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if threshold.Get() != 10 || name.Get() != "bar" ||
+		timeout.Get() != 5*time.Second || !enabled.Get() {
+		t.Errorf("got: %d, %s, %v, %t", threshold.Get(), name.Get(), timeout.Get(), enabled.Get())
+	}
+}
+
+func TestRegistry_LoadIsAtomic(t *testing.T) {
+	tests := map[string]string{
+		`validation`:  "name = bar\nthreshold = -1",
+		`parsing`:     "name = bar\ntimeout = soon",
+		`unknown key`: "name = bar\nfoo = 1",
+		`syntax`:      "name = bar\nthreshold",
+	}
+	for name, input := range tests {
+		input := input
+		t.Run(name, func(t *testing.T) {
+			r, _, nameConfig, _, _ := newTestRegistry()
+			if err := r.Load([]byte(input)); err == nil {
+				t.Fatal("expected an error")
+			}
+			if nameConfig.Get() != "foo" {
+				t.Errorf("partial update applied: %s", nameConfig.Get())
+			}
+		})
+	}
+}
+
+func TestRegistry_Watch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(path, []byte("threshold = 10"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	r, threshold, _, _, _ := newTestRegistry()
+	changes := make(chan int, 10)
+	threshold.Subscribe(func(old, new int) {
+		changes <- new
+	})
+	errs := make(chan error, 10)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go r.Watch(ctx, path, time.Millisecond, func(err error) { errs <- err })
+
+	expectChange := func(expected int) {
+		t.Helper()
+		select {
+		case v := <-changes:
+			if v != expected {
+				t.Errorf("got: %d, expected: %d", v, expected)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("no change notified")
+		}
+	}
+	expectChange(10)
+
+	if err := os.WriteFile(path, []byte("threshold = -50"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case err := <-errs:
+		if errors.Is(err, os.ErrNotExist) {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no error reported")
+	}
+
+	if err := os.WriteFile(path, []byte("threshold = 200"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	expectChange(200)
+}
+
+func TestRegistry_WatchSameSizeRewrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config")
+	mtime := time.Now().Add(-time.Hour)
+	write := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		// Same size and modification time: only the content differs
+		if err := os.Chtimes(path, mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("threshold = 11")
+
+	r, threshold, _, _, _ := newTestRegistry()
+	changes := make(chan int, 10)
+	threshold.Subscribe(func(old, new int) {
+		changes <- new
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	// A nil onError is allowed
+	go r.Watch(ctx, path, time.Millisecond, nil)
+
+	for _, expected := range []int{11, 12} {
+		select {
+		case v := <-changes:
+			if v != expected {
+				t.Errorf("got: %d, expected: %d", v, expected)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("%d: no change notified", expected)
+		}
+		write("threshold = 12")
+	}
+}
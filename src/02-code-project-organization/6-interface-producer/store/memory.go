@@ -0,0 +1,81 @@
+package store
+
+import (
+	"errors"
+	"sort"
+	"sync"
+)
+
+// InMemory is a CustomerStorage keeping the customers in a map.
+type InMemory struct {
+	mu        sync.RWMutex
+	customers map[string]Customer
+}
+
+var _ CustomerStorage = (*InMemory)(nil)
+
+func NewInMemory() *InMemory {
+	return &InMemory{customers: make(map[string]Customer)}
+}
+
+func (s *InMemory) StoreCustomer(customer Customer) error {
+	if customer.ID == "" {
+		return errors.New("customer id should be set")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, exists := s.customers[customer.ID]; exists {
+		return ErrCustomerExists
+	}
+	s.customers[customer.ID] = customer
+	return nil
+}
+
+func (s *InMemory) GetCustomer(id string) (Customer, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	customer, exists := s.customers[id]
+	if !exists {
+		return Customer{}, ErrCustomerNotFound
+	}
+	return customer, nil
+}
+
+func (s *InMemory) UpdateCustomer(customer Customer) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, exists := s.customers[customer.ID]; !exists {
+		return ErrCustomerNotFound
+	}
+	s.customers[customer.ID] = customer
+	return nil
+}
+
+func (s *InMemory) GetAllCustomers() ([]Customer, error) {
+	return s.filter(func(Customer) bool { return true }), nil
+}
+
+func (s *InMemory) GetCustomersWithoutContract() ([]Customer, error) {
+	return s.filter(func(c Customer) bool { return !c.HasContract() }), nil
+}
+
+func (s *InMemory) GetCustomersWithNegativeBalance() ([]Customer, error) {
+	return s.filter(func(c Customer) bool { return c.Balance < 0 }), nil
+}
+
+// filter returns the customers matching keep, sorted by ID like the SQL
+// implementation.
+func (s *InMemory) filter(keep func(Customer) bool) []Customer {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	customers := make([]Customer, 0, len(s.customers))
+	for _, c := range s.customers {
+		if keep(c) {
+			customers = append(customers, c)
+		}
+	}
+	sort.Slice(customers, func(i, j int) bool {
+		return customers[i].ID < customers[j].ID
+	})
+	return customers
+}
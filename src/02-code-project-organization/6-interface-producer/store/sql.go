@@ -0,0 +1,132 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// SQL is a CustomerStorage backed by a database/sql handle. The queries use
+// the ? placeholder and the ON CONFLICT clause, as understood by SQLite.
+type SQL struct {
+	db *sql.DB
+}
+
+var _ CustomerStorage = (*SQL)(nil)
+
+const schema = `CREATE TABLE IF NOT EXISTS customers (
+	id          TEXT PRIMARY KEY,
+	balance     REAL NOT NULL,
+	contract_id TEXT
+)`
+
+// NewSQL returns a SQL storage, creating the customers table if needed.
+func NewSQL(db *sql.DB) (*SQL, error) {
+	if _, err := db.Exec(schema); err != nil {
+		return nil, fmt.Errorf("creating schema: %w", err)
+	}
+	return &SQL{db: db}, nil
+}
+
+// StoreCustomer relies on the primary key to detect an existing customer:
+// checking before inserting would race with a concurrent insertion.
+func (s *SQL) StoreCustomer(customer Customer) error {
+	if customer.ID == "" {
+		return errors.New("customer id should be set")
+	}
+	res, err := s.db.Exec(`INSERT INTO customers (id, balance, contract_id) VALUES (?, ?, ?)
+		ON CONFLICT (id) DO NOTHING`,
+		customer.ID, customer.Balance, contractID(customer))
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrCustomerExists
+	}
+	return nil
+}
+
+func (s *SQL) GetCustomer(id string) (Customer, error) {
+	row := s.db.QueryRow(`SELECT id, balance, contract_id FROM customers WHERE id = ?`, id)
+	customer, err := scanCustomer(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Customer{}, ErrCustomerNotFound
+	}
+	return customer, err
+}
+
+func (s *SQL) UpdateCustomer(customer Customer) error {
+	res, err := s.db.Exec(`UPDATE customers SET balance = ?, contract_id = ? WHERE id = ?`,
+		customer.Balance, contractID(customer), customer.ID)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrCustomerNotFound
+	}
+	return nil
+}
+
+func (s *SQL) GetAllCustomers() ([]Customer, error) {
+	return s.query(`SELECT id, balance, contract_id FROM customers ORDER BY id`)
+}
+
+func (s *SQL) GetCustomersWithoutContract() ([]Customer, error) {
+	return s.query(`SELECT id, balance, contract_id FROM customers
+		WHERE contract_id IS NULL ORDER BY id`)
+}
+
+func (s *SQL) GetCustomersWithNegativeBalance() ([]Customer, error) {
+	return s.query(`SELECT id, balance, contract_id FROM customers
+		WHERE balance < 0 ORDER BY id`)
+}
+
+func (s *SQL) query(query string) ([]Customer, error) {
+	rows, err := s.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	customers := []Customer{}
+	for rows.Next() {
+		customer, err := scanCustomer(rows)
+		if err != nil {
+			return nil, err
+		}
+		customers = append(customers, customer)
+	}
+	return customers, rows.Err()
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanCustomer(s scanner) (Customer, error) {
+	var (
+		customer Customer
+		contract sql.NullString
+	)
+	if err := s.Scan(&customer.ID, &customer.Balance, &contract); err != nil {
+		return Customer{}, err
+	}
+	customer.ContractID = contract.String
+	return customer, nil
+}
+
+// contractID stores a customer without contract as NULL.
+func contractID(customer Customer) sql.NullString {
+	return sql.NullString{
+		String: customer.ContractID,
+		Valid:  customer.HasContract(),
+	}
+}
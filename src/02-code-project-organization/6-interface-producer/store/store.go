@@ -1,5 +1,12 @@
 package store
 
+import "errors"
+
+var (
+	ErrCustomerNotFound = errors.New("customer not found")
+	ErrCustomerExists   = errors.New("customer already exists")
+)
+
 type CustomerStorage interface {
 	StoreCustomer(customer Customer) error
 	GetCustomer(id string) (Customer, error)
@@ -9,4 +16,12 @@ type CustomerStorage interface {
 	GetCustomersWithNegativeBalance() ([]Customer, error)
 }
 
-type Customer struct{}
+type Customer struct {
+	ID         string
+	Balance    float64
+	ContractID string // Empty if the customer has no contract
+}
+
+func (c Customer) HasContract() bool {
+	return c.ContractID != ""
+}
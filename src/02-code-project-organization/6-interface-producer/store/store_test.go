@@ -0,0 +1,35 @@
+package store_test
+
+import (
+	"database/sql"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+
+	"github.com/teivah/100-go-mistakes/src/02-code-project-organization/6-interface-producer/store"
+	"github.com/teivah/100-go-mistakes/src/02-code-project-organization/6-interface-producer/store/storetest"
+)
+
+func TestInMemory(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) store.CustomerStorage {
+		return store.NewInMemory()
+	})
+}
+
+func TestSQL(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) store.CustomerStorage {
+		db, err := sql.Open("sqlite3", ":memory:")
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Each connection to :memory: is a distinct database
+		db.SetMaxOpenConns(1)
+		t.Cleanup(func() { _ = db.Close() })
+
+		s, err := store.NewSQL(db)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	})
+}
@@ -0,0 +1,146 @@
+// Package storetest provides a conformance test suite for the
+// store.CustomerStorage implementations.
+package storetest
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/teivah/100-go-mistakes/src/02-code-project-organization/6-interface-producer/store"
+)
+
+// Run runs the conformance suite. newStorage is called once per subtest and
+// should return an empty storage.
+func Run(t *testing.T, newStorage func(t *testing.T) store.CustomerStorage) {
+	tests := map[string]func(t *testing.T, s store.CustomerStorage){
+		"store and get":           testStoreAndGet,
+		"store existing":          testStoreExisting,
+		"store without id":        testStoreWithoutID,
+		"get missing":             testGetMissing,
+		"update":                  testUpdate,
+		"update missing":          testUpdateMissing,
+		"get all":                 testGetAll,
+		"without contract":        testWithoutContract,
+		"with negative balance":   testWithNegativeBalance,
+		"empty storage is listed": testEmpty,
+	}
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			test(t, newStorage(t))
+		})
+	}
+}
+
+var (
+	alice = store.Customer{ID: "alice", Balance: 100, ContractID: "c1"}
+	bob   = store.Customer{ID: "bob", Balance: -20.5}
+	carol = store.Customer{ID: "carol", Balance: -1, ContractID: "c2"}
+	dave  = store.Customer{ID: "dave", Balance: 0}
+)
+
+func storeAll(t *testing.T, s store.CustomerStorage, customers ...store.Customer) {
+	t.Helper()
+	for _, c := range customers {
+		if err := s.StoreCustomer(c); err != nil {
+			t.Fatalf("storing %s: %v", c.ID, err)
+		}
+	}
+}
+
+func assertCustomers(t *testing.T, got []store.Customer, err error, expected ...store.Customer) {
+	t.Helper()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) == 0 && len(expected) == 0 {
+		return
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("got: %v, expected: %v", got, expected)
+	}
+}
+
+func testStoreAndGet(t *testing.T, s store.CustomerStorage) {
+	storeAll(t, s, alice, bob)
+	got, err := s.GetCustomer(alice.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != alice {
+		t.Errorf("got: %v, expected: %v", got, alice)
+	}
+}
+
+func testStoreExisting(t *testing.T, s store.CustomerStorage) {
+	storeAll(t, s, alice)
+	err := s.StoreCustomer(store.Customer{ID: alice.ID})
+	if !errors.Is(err, store.ErrCustomerExists) {
+		t.Errorf("expected ErrCustomerExists, got: %v", err)
+	}
+	got, _ := s.GetCustomer(alice.ID)
+	if got != alice {
+		t.Errorf("existing customer overwritten: %v", got)
+	}
+}
+
+func testStoreWithoutID(t *testing.T, s store.CustomerStorage) {
+	if err := s.StoreCustomer(store.Customer{Balance: 1}); err == nil {
+		t.Error("expected an error")
+	}
+	got, err := s.GetAllCustomers()
+	assertCustomers(t, got, err)
+}
+
+func testGetMissing(t *testing.T, s store.CustomerStorage) {
+	_, err := s.GetCustomer("missing")
+	if !errors.Is(err, store.ErrCustomerNotFound) {
+		t.Errorf("expected ErrCustomerNotFound, got: %v", err)
+	}
+}
+
+func testUpdate(t *testing.T, s store.CustomerStorage) {
+	storeAll(t, s, alice)
+	updated := store.Customer{ID: alice.ID, Balance: -5}
+	if err := s.UpdateCustomer(updated); err != nil {
+		t.Fatal(err)
+	}
+	got, err := s.GetCustomer(alice.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != updated {
+		t.Errorf("got: %v, expected: %v", got, updated)
+	}
+}
+
+func testUpdateMissing(t *testing.T, s store.CustomerStorage) {
+	err := s.UpdateCustomer(store.Customer{ID: "missing"})
+	if !errors.Is(err, store.ErrCustomerNotFound) {
+		t.Errorf("expected ErrCustomerNotFound, got: %v", err)
+	}
+}
+
+func testGetAll(t *testing.T, s store.CustomerStorage) {
+	storeAll(t, s, dave, bob, carol, alice)
+	got, err := s.GetAllCustomers()
+	assertCustomers(t, got, err, alice, bob, carol, dave)
+}
+
+func testWithoutContract(t *testing.T, s store.CustomerStorage) {
+	storeAll(t, s, dave, bob, carol, alice)
+	got, err := s.GetCustomersWithoutContract()
+	assertCustomers(t, got, err, bob, dave)
+}
+
+func testWithNegativeBalance(t *testing.T, s store.CustomerStorage) {
+	storeAll(t, s, dave, bob, carol, alice)
+	got, err := s.GetCustomersWithNegativeBalance()
+	assertCustomers(t, got, err, bob, carol)
+}
+
+func testEmpty(t *testing.T, s store.CustomerStorage) {
+	got, err := s.GetAllCustomers()
+	assertCustomers(t, got, err)
+}
@@ -1,17 +1,25 @@
 package store
 
-func (s *Store) GetContract(id string) (Contract, error) {
-	return Contract{}, nil
+// GetContract returns the contract and its version, to be passed to
+// SetContract.
+func (s *Store) GetContract(id string) (Contract, Version, error) {
+	return s.Contracts.Get(id)
 }
 
-func (s *Store) SetContract(id string, contract Contract) error {
-	return nil
+// SetContract stores the contract if its current version is expected (0 to
+// create it), otherwise it returns a VersionConflictError.
+func (s *Store) SetContract(id string, contract Contract, expected Version) (Version, error) {
+	return s.Contracts.Set(id, contract, expected)
 }
 
-func (s *Store) GetCustomer(id string) (Customer, error) {
-	return Customer{}, nil
+// GetCustomer returns the customer and its version, to be passed to
+// SetCustomer.
+func (s *Store) GetCustomer(id string) (Customer, Version, error) {
+	return s.Customers.Get(id)
 }
 
-func (s *Store) SetCustomer(id string, customer Customer) error {
-	return nil
+// SetCustomer stores the customer if its current version is expected (0 to
+// create it), otherwise it returns a VersionConflictError.
+func (s *Store) SetCustomer(id string, customer Customer, expected Version) (Version, error) {
+	return s.Customers.Set(id, customer, expected)
 }
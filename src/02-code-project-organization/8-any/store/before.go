@@ -1,19 +1,30 @@
 package store
 
+import "time"
+
 type Customer struct {
-	// Some fields
+	ID      string
+	Name    string
+	Balance float64
+	Tags    []string
 }
 
 type Contract struct {
-	// Some fields
+	ID         string
+	CustomerID string
+	Amount     float64
+	Start      time.Time
+	End        time.Time
 }
 
-type Store struct{}
-
-func (s *Store) Get(id string) (any, error) {
-	return nil, nil
+type Store struct {
+	Customers *Repository[Customer]
+	Contracts *Repository[Contract]
 }
 
-func (s *Store) Set(id string, v any) error {
-	return nil
+func NewStore(codec Codec) *Store {
+	return &Store{
+		Customers: NewRepository[Customer](codec),
+		Contracts: NewRepository[Contract](codec),
+	}
 }
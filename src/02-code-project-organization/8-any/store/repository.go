@@ -0,0 +1,130 @@
+package store
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// Codec serializes the values kept by a Repository.
+type Codec interface {
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+}
+
+type JSONCodec struct{}
+
+func (JSONCodec) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (JSONCodec) Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
+
+type GobCodec struct{}
+
+func (GobCodec) Marshal(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (GobCodec) Unmarshal(data []byte, v any) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(v)
+}
+
+// Version is incremented each time a value is set. A value that was never
+// set has the version 0.
+type Version uint64
+
+type NotFoundError struct {
+	Type string
+	ID   string
+}
+
+func (e NotFoundError) Error() string {
+	return fmt.Sprintf("%s %q not found", e.Type, e.ID)
+}
+
+type VersionConflictError struct {
+	ID       string
+	Expected Version
+	Actual   Version
+}
+
+func (e VersionConflictError) Error() string {
+	return fmt.Sprintf("version conflict on %q: expected %d, got %d", e.ID, e.Expected, e.Actual)
+}
+
+type entry struct {
+	data    []byte
+	version Version
+}
+
+// Repository stores values of type T in their serialized form. Hence, the
+// values returned by Get never alias the ones passed to Set.
+type Repository[T any] struct {
+	mu      sync.RWMutex
+	codec   Codec
+	entries map[string]entry
+}
+
+func NewRepository[T any](codec Codec) *Repository[T] {
+	return &Repository[T]{
+		codec:   codec,
+		entries: make(map[string]entry),
+	}
+}
+
+// Get returns the value and its current version, or a NotFoundError.
+func (r *Repository[T]) Get(id string) (T, Version, error) {
+	var v T
+	r.mu.RLock()
+	e, exists := r.entries[id]
+	r.mu.RUnlock()
+	if !exists {
+		return v, 0, NotFoundError{Type: fmt.Sprintf("%T", v), ID: id}
+	}
+	if err := r.codec.Unmarshal(e.data, &v); err != nil {
+		return v, 0, fmt.Errorf("unmarshaling %q: %w", id, err)
+	}
+	return v, e.version, nil
+}
+
+// Set stores v only if the current version of id is expected (0 to create
+// it), otherwise it returns a VersionConflictError. It returns the new
+// version.
+func (r *Repository[T]) Set(id string, v T, expected Version) (Version, error) {
+	data, err := r.codec.Marshal(v)
+	if err != nil {
+		return 0, fmt.Errorf("marshaling %q: %w", id, err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	current := r.entries[id].version
+	if current != expected {
+		return 0, VersionConflictError{ID: id, Expected: expected, Actual: current}
+	}
+	r.entries[id] = entry{data: data, version: current + 1}
+	return current + 1, nil
+}
+
+// Put stores v regardless of the current version and returns the new one.
+func (r *Repository[T]) Put(id string, v T) (Version, error) {
+	data, err := r.codec.Marshal(v)
+	if err != nil {
+		return 0, fmt.Errorf("marshaling %q: %w", id, err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	version := r.entries[id].version + 1
+	r.entries[id] = entry{data: data, version: version}
+	return version, nil
+}
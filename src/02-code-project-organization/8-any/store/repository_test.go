@@ -0,0 +1,110 @@
+package store
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRepository(t *testing.T) {
+	codecs := map[string]Codec{
+		"json": JSONCodec{},
+		"gob":  GobCodec{},
+	}
+	for name, codec := range codecs {
+		codec := codec
+		t.Run(name, func(t *testing.T) {
+			s := NewStore(codec)
+
+			customer := Customer{ID: "c1", Name: "Alice", Balance: 42.5, Tags: []string{"vip"}}
+			if _, err := s.SetCustomer(customer.ID, customer, 0); err != nil {
+				t.Fatal(err)
+			}
+			customer.Tags[0] = "mutated"
+			got, version, err := s.GetCustomer("c1")
+			if err != nil {
+				t.Fatal(err)
+			}
+			expected := Customer{ID: "c1", Name: "Alice", Balance: 42.5, Tags: []string{"vip"}}
+			if !reflect.DeepEqual(got, expected) {
+				t.Errorf("got: %+v, expected: %+v", got, expected)
+			}
+			var conflict VersionConflictError
+			if _, err := s.SetCustomer(customer.ID, customer, 0); !errors.As(err, &conflict) {
+				t.Errorf("expected VersionConflictError, got: %v", err)
+			}
+			if _, err := s.SetCustomer(customer.ID, customer, version); err != nil {
+				t.Error(err)
+			}
+
+			contract := Contract{
+				ID:         "k1",
+				CustomerID: "c1",
+				Amount:     1000,
+				Start:      time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+				End:        time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+			}
+			if _, err := s.SetContract(contract.ID, contract, 0); err != nil {
+				t.Fatal(err)
+			}
+			gotContract, _, err := s.GetContract("k1")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(gotContract, contract) {
+				t.Errorf("got: %+v, expected: %+v", gotContract, contract)
+			}
+		})
+	}
+}
+
+func TestRepository_NotFound(t *testing.T) {
+	s := NewStore(JSONCodec{})
+	_, _, err := s.GetContract("missing")
+	var notFound NotFoundError
+	if !errors.As(err, &notFound) {
+		t.Fatalf("expected NotFoundError, got: %v", err)
+	}
+	if notFound.ID != "missing" || notFound.Type != "store.Contract" {
+		t.Errorf("got: %+v", notFound)
+	}
+}
+
+func TestRepository_Versioning(t *testing.T) {
+	r := NewRepository[Customer](GobCodec{})
+
+	v1, err := r.Set("c1", Customer{Name: "Alice"}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Set("c1", Customer{Name: "Bob"}, 0); err == nil {
+		t.Error("expected a conflict when creating an existing value")
+	}
+
+	_, version, err := r.Get("c1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version != v1 {
+		t.Errorf("got version %d, expected %d", version, v1)
+	}
+	v2, err := r.Set("c1", Customer{Name: "Carol"}, version)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = r.Set("c1", Customer{Name: "Dave"}, v1)
+	var conflict VersionConflictError
+	if !errors.As(err, &conflict) {
+		t.Fatalf("expected VersionConflictError, got: %v", err)
+	}
+	if conflict.Expected != v1 || conflict.Actual != v2 {
+		t.Errorf("got: %+v", conflict)
+	}
+
+	got, _, _ := r.Get("c1")
+	if got.Name != "Carol" {
+		t.Errorf("got: %s", got.Name)
+	}
+}
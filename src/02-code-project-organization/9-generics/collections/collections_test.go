@@ -0,0 +1,216 @@
+package collections
+
+import (
+	"container/heap"
+	"container/list"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestSortedKeys(t *testing.T) {
+	type id string
+	got := SortedKeys(map[id]int{"c": 3, "a": 1, "b": 2})
+	expected := []id{"a", "b", "c"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("got: %v, expected: %v", got, expected)
+	}
+}
+
+func TestList(t *testing.T) {
+	var l List[int]
+	if l.Front() != nil || l.Back() != nil {
+		t.Error("expected an empty list")
+	}
+	two := l.PushBack(2)
+	l.PushBack(3)
+	l.PushFront(1)
+	if got := l.Values(); !reflect.DeepEqual(got, []int{1, 2, 3}) {
+		t.Errorf("got: %v", got)
+	}
+
+	if v := l.Remove(two); v != 2 {
+		t.Errorf("removed: %d", v)
+	}
+	l.Remove(two) // No-op
+	if l.Len() != 2 {
+		t.Errorf("len: %d", l.Len())
+	}
+
+	var backward []int
+	for e := l.Back(); e != nil; e = e.Prev() {
+		backward = append(backward, e.Val)
+	}
+	if !reflect.DeepEqual(backward, []int{3, 1}) {
+		t.Errorf("got: %v", backward)
+	}
+
+	var first []int
+	l.Each(func(v int) bool {
+		first = append(first, v)
+		return false
+	})
+	if !reflect.DeepEqual(first, []int{1}) {
+		t.Errorf("got: %v", first)
+	}
+}
+
+func TestOrderedSet(t *testing.T) {
+	s := NewOrderedSet(5, 1, 3, 1)
+	if !reflect.DeepEqual(s.Values(), []int{1, 3, 5}) {
+		t.Errorf("got: %v", s.Values())
+	}
+	if s.Add(3) {
+		t.Error("3 already present")
+	}
+	if !s.Remove(1) || s.Remove(1) {
+		t.Error("unexpected remove result")
+	}
+	if s.Contains(1) || !s.Contains(5) {
+		t.Error("unexpected contains result")
+	}
+	if min, _ := s.Min(); min != 3 {
+		t.Errorf("min: %d", min)
+	}
+	if max, _ := s.Max(); max != 5 {
+		t.Errorf("max: %d", max)
+	}
+
+	var empty OrderedSet[string]
+	if _, ok := empty.Min(); ok {
+		t.Error("expected no min")
+	}
+}
+
+func TestHeap(t *testing.T) {
+	input := []int{5, 2, 8, 1, 9, 3, 3}
+
+	min := NewMinHeap[int]()
+	max := NewMaxHeap[int]()
+	for _, v := range input {
+		min.Push(v)
+		max.Push(v)
+	}
+
+	sorted := append([]int(nil), input...)
+	sort.Ints(sorted)
+	for i := range sorted {
+		v, _ := min.Pop()
+		if v != sorted[i] {
+			t.Fatalf("min heap: got %d, expected %d", v, sorted[i])
+		}
+		v, _ = max.Pop()
+		if v != sorted[len(sorted)-1-i] {
+			t.Fatalf("max heap: got %d, expected %d", v, sorted[len(sorted)-1-i])
+		}
+	}
+	if _, ok := min.Pop(); ok {
+		t.Error("expected an empty heap")
+	}
+}
+
+const n = 10_000
+
+var globalInt int
+
+func BenchmarkListGenerics(b *testing.B) {
+	var local int
+	for i := 0; i < b.N; i++ {
+		var l List[int]
+		for j := 0; j < n; j++ {
+			l.PushBack(j)
+		}
+		for e := l.Front(); e != nil; e = e.Next() {
+			local += e.Val
+		}
+	}
+	globalInt = local
+}
+
+func BenchmarkListInterface(b *testing.B) {
+	var local int
+	for i := 0; i < b.N; i++ {
+		l := list.New()
+		for j := 0; j < n; j++ {
+			l.PushBack(j)
+		}
+		for e := l.Front(); e != nil; e = e.Next() {
+			local += e.Value.(int)
+		}
+	}
+	globalInt = local
+}
+
+func BenchmarkOrderedSetGenerics(b *testing.B) {
+	var local int
+	for i := 0; i < b.N; i++ {
+		s := NewOrderedSet[int]()
+		for j := 0; j < n; j++ {
+			s.Add((j * 7919) % n)
+		}
+		local = s.Len()
+	}
+	globalInt = local
+}
+
+func BenchmarkOrderedSetInterface(b *testing.B) {
+	var local int
+	for i := 0; i < b.N; i++ {
+		var values []any
+		for j := 0; j < n; j++ {
+			v := (j * 7919) % n
+			k := sort.Search(len(values), func(k int) bool {
+				return values[k].(int) >= v
+			})
+			if k < len(values) && values[k].(int) == v {
+				continue
+			}
+			values = append(values, nil)
+			copy(values[k+1:], values[k:])
+			values[k] = v
+		}
+		local = len(values)
+	}
+	globalInt = local
+}
+
+func BenchmarkHeapGenerics(b *testing.B) {
+	var local int
+	for i := 0; i < b.N; i++ {
+		h := NewMinHeap[int]()
+		for j := 0; j < n; j++ {
+			h.Push((j * 7919) % n)
+		}
+		for h.Len() > 0 {
+			local, _ = h.Pop()
+		}
+	}
+	globalInt = local
+}
+
+type intHeap []any
+
+func (h intHeap) Len() int           { return len(h) }
+func (h intHeap) Less(i, j int) bool { return h[i].(int) < h[j].(int) }
+func (h intHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *intHeap) Push(x any)        { *h = append(*h, x) }
+func (h *intHeap) Pop() any {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
+
+func BenchmarkHeapInterface(b *testing.B) {
+	var local int
+	for i := 0; i < b.N; i++ {
+		h := &intHeap{}
+		for j := 0; j < n; j++ {
+			heap.Push(h, (j*7919)%n)
+		}
+		for h.Len() > 0 {
+			local = heap.Pop(h).(int)
+		}
+	}
+	globalInt = local
+}
@@ -0,0 +1,11 @@
+// Package collections provides generic data structures.
+package collections
+
+// Ordered is satisfied by the types supporting the < operator, including the
+// ones whose underlying type is listed (e.g. type ID string).
+type Ordered interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64 |
+		~string
+}
@@ -0,0 +1,83 @@
+package collections
+
+// Heap is a binary heap whose top is the element for which less returns
+// true against all the others.
+type Heap[T any] struct {
+	values []T
+	less   func(a, b T) bool
+}
+
+func NewHeap[T any](less func(a, b T) bool) *Heap[T] {
+	return &Heap[T]{less: less}
+}
+
+func NewMinHeap[T Ordered]() *Heap[T] {
+	return NewHeap(func(a, b T) bool { return a < b })
+}
+
+func NewMaxHeap[T Ordered]() *Heap[T] {
+	return NewHeap(func(a, b T) bool { return a > b })
+}
+
+func (h *Heap[T]) Len() int {
+	return len(h.values)
+}
+
+func (h *Heap[T]) Push(v T) {
+	h.values = append(h.values, v)
+	h.up(len(h.values) - 1)
+}
+
+// Peek returns the top without removing it, or false if the heap is empty.
+func (h *Heap[T]) Peek() (T, bool) {
+	var zero T
+	if len(h.values) == 0 {
+		return zero, false
+	}
+	return h.values[0], true
+}
+
+// Pop removes and returns the top, or false if the heap is empty.
+func (h *Heap[T]) Pop() (T, bool) {
+	var zero T
+	if len(h.values) == 0 {
+		return zero, false
+	}
+	n := len(h.values) - 1
+	top := h.values[0]
+	h.values[0] = h.values[n]
+	h.values[n] = zero
+	h.values = h.values[:n]
+	h.down(0)
+	return top, true
+}
+
+func (h *Heap[T]) up(i int) {
+	for i > 0 {
+		parent := (i - 1) / 2
+		if !h.less(h.values[i], h.values[parent]) {
+			return
+		}
+		h.values[i], h.values[parent] = h.values[parent], h.values[i]
+		i = parent
+	}
+}
+
+func (h *Heap[T]) down(i int) {
+	n := len(h.values)
+	for {
+		smallest := i
+		left, right := 2*i+1, 2*i+2
+		if left < n && h.less(h.values[left], h.values[smallest]) {
+			smallest = left
+		}
+		if right < n && h.less(h.values[right], h.values[smallest]) {
+			smallest = right
+		}
+		if smallest == i {
+			return
+		}
+		h.values[i], h.values[smallest] = h.values[smallest], h.values[i]
+		i = smallest
+	}
+}
@@ -0,0 +1,110 @@
+package collections
+
+// Element is an element of a List.
+type Element[T any] struct {
+	Val        T
+	next, prev *Element[T]
+	list       *List[T]
+}
+
+// Next returns the next element or nil.
+func (e *Element[T]) Next() *Element[T] {
+	if e.list == nil || e.next == &e.list.root {
+		return nil
+	}
+	return e.next
+}
+
+// Prev returns the previous element or nil.
+func (e *Element[T]) Prev() *Element[T] {
+	if e.list == nil || e.prev == &e.list.root {
+		return nil
+	}
+	return e.prev
+}
+
+// List is a doubly linked list. The zero value is an empty list ready to
+// use.
+type List[T any] struct {
+	// root is a sentinel: root.next is the front and root.prev the back
+	root Element[T]
+	len  int
+}
+
+func (l *List[T]) lazyInit() {
+	if l.root.next == nil {
+		l.root.next = &l.root
+		l.root.prev = &l.root
+	}
+}
+
+func (l *List[T]) Len() int {
+	return l.len
+}
+
+// Front returns the first element or nil.
+func (l *List[T]) Front() *Element[T] {
+	if l.len == 0 {
+		return nil
+	}
+	return l.root.next
+}
+
+// Back returns the last element or nil.
+func (l *List[T]) Back() *Element[T] {
+	if l.len == 0 {
+		return nil
+	}
+	return l.root.prev
+}
+
+func (l *List[T]) insertAfter(v T, at *Element[T]) *Element[T] {
+	e := &Element[T]{Val: v, list: l, prev: at, next: at.next}
+	at.next.prev = e
+	at.next = e
+	l.len++
+	return e
+}
+
+func (l *List[T]) PushFront(v T) *Element[T] {
+	l.lazyInit()
+	return l.insertAfter(v, &l.root)
+}
+
+func (l *List[T]) PushBack(v T) *Element[T] {
+	l.lazyInit()
+	return l.insertAfter(v, l.root.prev)
+}
+
+// Remove removes e if it belongs to l and returns its value.
+func (l *List[T]) Remove(e *Element[T]) T {
+	if e.list == l {
+		e.prev.next = e.next
+		e.next.prev = e.prev
+		// Avoid memory leaks
+		e.next = nil
+		e.prev = nil
+		e.list = nil
+		l.len--
+	}
+	return e.Val
+}
+
+// Each calls f on each value from front to back until f returns false.
+func (l *List[T]) Each(f func(T) bool) {
+	for e := l.Front(); e != nil; e = e.Next() {
+		if !f(e.Val) {
+			return
+		}
+	}
+}
+
+// Values returns the values from front to back.
+func (l *List[T]) Values() []T {
+	values := make([]T, 0, l.len)
+	l.Each(func(v T) bool {
+		values = append(values, v)
+		return true
+	})
+	return values
+}
@@ -0,0 +1,81 @@
+package collections
+
+import "sort"
+
+// OrderedSet is a set keeping its values sorted. The zero value is an empty
+// set ready to use.
+type OrderedSet[T Ordered] struct {
+	values []T
+}
+
+func NewOrderedSet[T Ordered](values ...T) *OrderedSet[T] {
+	s := &OrderedSet[T]{}
+	for _, v := range values {
+		s.Add(v)
+	}
+	return s
+}
+
+func (s *OrderedSet[T]) search(v T) (int, bool) {
+	i := sort.Search(len(s.values), func(i int) bool {
+		return s.values[i] >= v
+	})
+	return i, i < len(s.values) && s.values[i] == v
+}
+
+// Add adds v and returns false if it was already present.
+func (s *OrderedSet[T]) Add(v T) bool {
+	i, found := s.search(v)
+	if found {
+		return false
+	}
+	var zero T
+	s.values = append(s.values, zero)
+	copy(s.values[i+1:], s.values[i:])
+	s.values[i] = v
+	return true
+}
+
+// Remove removes v and returns false if it wasn't present.
+func (s *OrderedSet[T]) Remove(v T) bool {
+	i, found := s.search(v)
+	if !found {
+		return false
+	}
+	s.values = append(s.values[:i], s.values[i+1:]...)
+	return true
+}
+
+func (s *OrderedSet[T]) Contains(v T) bool {
+	_, found := s.search(v)
+	return found
+}
+
+func (s *OrderedSet[T]) Len() int {
+	return len(s.values)
+}
+
+// Values returns a copy of the values in ascending order.
+func (s *OrderedSet[T]) Values() []T {
+	values := make([]T, len(s.values))
+	copy(values, s.values)
+	return values
+}
+
+// Min returns the smallest value, or false if the set is empty.
+func (s *OrderedSet[T]) Min() (T, bool) {
+	var zero T
+	if len(s.values) == 0 {
+		return zero, false
+	}
+	return s.values[0], true
+}
+
+// Max returns the largest value, or false if the set is empty.
+func (s *OrderedSet[T]) Max() (T, bool) {
+	var zero T
+	if len(s.values) == 0 {
+		return zero, false
+	}
+	return s.values[len(s.values)-1], true
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/teivah/100-go-mistakes/src/02-code-project-organization/9-generics/collections"
+)
+
+const n = 10_000
+
+func benchmarkMap() map[string]int {
+	m := make(map[string]int, n)
+	for i := 0; i < n; i++ {
+		m[string(rune('a'+i%26))+string(rune(i))] = i
+	}
+	return m
+}
+
+var globalKeys []string
+
+func BenchmarkGetKeys(b *testing.B) {
+	m := benchmarkMap()
+	b.ResetTimer()
+	var local []string
+	for i := 0; i < b.N; i++ {
+		keys, _ := getKeys(m)
+		// Converting back from any is part of the cost
+		local = make([]string, 0, len(keys))
+		for _, k := range keys {
+			local = append(local, k.(string))
+		}
+		sort.Strings(local)
+	}
+	globalKeys = local
+}
+
+func BenchmarkGetKeysGenerics(b *testing.B) {
+	m := benchmarkMap()
+	b.ResetTimer()
+	var local []string
+	for i := 0; i < b.N; i++ {
+		local = getKeysGenerics(m)
+		sort.Strings(local)
+	}
+	globalKeys = local
+}
+
+func BenchmarkSortedKeys(b *testing.B) {
+	m := benchmarkMap()
+	b.ResetTimer()
+	var local []string
+	for i := 0; i < b.N; i++ {
+		local = collections.SortedKeys(m)
+	}
+	globalKeys = local
+}
@@ -0,0 +1,139 @@
+// Package checked provides integer arithmetic detecting overflows. Each
+// operation comes in three variants:
+//   - Add, Sub, ... return ErrOverflow instead of a wrong result
+//   - SaturatingAdd, ... clamp the result to the bounds of the type
+//   - WrappingAdd, ... wrap around like the Go operators, explicitly
+package checked
+
+import (
+	"errors"
+	"unsafe"
+)
+
+var (
+	ErrOverflow     = errors.New("integer overflow")
+	ErrDivideByZero = errors.New("integer divide by zero")
+)
+
+type Signed interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64
+}
+
+type Unsigned interface {
+	~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+type Integer interface {
+	Signed | Unsigned
+}
+
+func isSigned[T Integer]() bool {
+	var zero T
+	return ^zero < 0
+}
+
+// Bounds returns the smallest and largest values of T.
+func Bounds[T Integer]() (min, max T) {
+	var zero T
+	if !isSigned[T]() {
+		return 0, ^zero
+	}
+	bits := unsafe.Sizeof(zero) * 8
+	max = T(uint64(1)<<(bits-1) - 1)
+	return -max - 1, max
+}
+
+func Add[T Integer](a, b T) (T, error) {
+	c := a + b
+	if isSigned[T]() {
+		// Overflow if both operands have the same sign and the result doesn't
+		if (a >= 0) == (b >= 0) && (c >= 0) != (a >= 0) {
+			return 0, ErrOverflow
+		}
+		return c, nil
+	}
+	if c < a {
+		return 0, ErrOverflow
+	}
+	return c, nil
+}
+
+func Sub[T Integer](a, b T) (T, error) {
+	c := a - b
+	if isSigned[T]() {
+		// Overflow if the operands have different signs and the result
+		// doesn't have the sign of a
+		if (a >= 0) != (b >= 0) && (c >= 0) != (a >= 0) {
+			return 0, ErrOverflow
+		}
+		return c, nil
+	}
+	if a < b {
+		return 0, ErrOverflow
+	}
+	return c, nil
+}
+
+func Mul[T Integer](a, b T) (T, error) {
+	if a == 0 || b == 0 {
+		return 0, nil
+	}
+	c := a * b
+	if isSigned[T]() {
+		min, _ := Bounds[T]()
+		minusOne := ^T(0)
+		if (a == minusOne && b == min) || (b == minusOne && a == min) {
+			return 0, ErrOverflow
+		}
+	}
+	if c/b != a {
+		return 0, ErrOverflow
+	}
+	return c, nil
+}
+
+// Div returns a / b truncated towards zero.
+func Div[T Integer](a, b T) (T, error) {
+	if b == 0 {
+		return 0, ErrDivideByZero
+	}
+	if isSigned[T]() {
+		min, _ := Bounds[T]()
+		if a == min && b == ^T(0) { // ^T(0) is -1 for signed types
+			return 0, ErrOverflow
+		}
+	}
+	return a / b, nil
+}
+
+// Neg returns -a. For unsigned types, only 0 can be negated.
+func Neg[T Integer](a T) (T, error) {
+	if isSigned[T]() {
+		min, _ := Bounds[T]()
+		if a == min {
+			return 0, ErrOverflow
+		}
+		return -a, nil
+	}
+	if a != 0 {
+		return 0, ErrOverflow
+	}
+	return 0, nil
+}
+
+// Convert converts v to To if it's representable.
+func Convert[To, From Integer](v From) (To, error) {
+	if fits[To](v) {
+		return To(v), nil
+	}
+	return 0, ErrOverflow
+}
+
+func fits[To, From Integer](v From) bool {
+	min, max := Bounds[To]()
+	if v < 0 {
+		// From is signed, and so is To if min < 0
+		return min < 0 && int64(v) >= int64(min)
+	}
+	return uint64(v) <= uint64(max)
+}
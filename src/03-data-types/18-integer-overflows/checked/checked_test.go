@@ -0,0 +1,206 @@
+package checked
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"testing"
+)
+
+func toBig[T Integer](v T) *big.Int {
+	if isSigned[T]() {
+		return big.NewInt(int64(v))
+	}
+	return new(big.Int).SetUint64(uint64(v))
+}
+
+func inBounds[T Integer](v *big.Int) bool {
+	min, max := Bounds[T]()
+	return v.Cmp(toBig(min)) >= 0 && v.Cmp(toBig(max)) <= 0
+}
+
+func clamp[T Integer](v *big.Int) *big.Int {
+	min, max := Bounds[T]()
+	if v.Cmp(toBig(min)) < 0 {
+		return toBig(min)
+	}
+	if v.Cmp(toBig(max)) > 0 {
+		return toBig(max)
+	}
+	return v
+}
+
+// wrap reduces v modulo 2^bits into the range of T.
+func wrap[T Integer](v *big.Int) *big.Int {
+	min, max := Bounds[T]()
+	size := new(big.Int).Sub(toBig(max), toBig(min))
+	size.Add(size, big.NewInt(1))
+	r := new(big.Int).Sub(v, toBig(min))
+	r.Mod(r, size)
+	return r.Add(r, toBig(min))
+}
+
+type op[T Integer] struct {
+	name       string
+	checked    func(a, b T) (T, error)
+	saturating func(a, b T) T
+	wrapping   func(a, b T) T
+	expected   func(a, b *big.Int) *big.Int
+}
+
+func ops[T Integer]() []op[T] {
+	return []op[T]{
+		{"add", Add[T], SaturatingAdd[T], WrappingAdd[T], func(a, b *big.Int) *big.Int { return new(big.Int).Add(a, b) }},
+		{"sub", Sub[T], SaturatingSub[T], WrappingSub[T], func(a, b *big.Int) *big.Int { return new(big.Int).Sub(a, b) }},
+		{"mul", Mul[T], SaturatingMul[T], WrappingMul[T], func(a, b *big.Int) *big.Int { return new(big.Int).Mul(a, b) }},
+		{"div", Div[T], SaturatingDiv[T], WrappingDiv[T], func(a, b *big.Int) *big.Int { return new(big.Int).Quo(a, b) }},
+		{"neg", func(a, _ T) (T, error) { return Neg(a) }, func(a, _ T) T { return SaturatingNeg(a) },
+			func(a, _ T) T { return WrappingNeg(a) }, func(a, _ *big.Int) *big.Int { return new(big.Int).Neg(a) }},
+	}
+}
+
+func check[T Integer](t *testing.T, a, b T) {
+	t.Helper()
+	for _, op := range ops[T]() {
+		if op.name == "div" && b == 0 {
+			if _, err := op.checked(a, b); !errors.Is(err, ErrDivideByZero) {
+				t.Errorf("%T %d / 0: expected ErrDivideByZero, got %v", a, a, err)
+			}
+			continue
+		}
+
+		expected := op.expected(toBig(a), toBig(b))
+		got, err := op.checked(a, b)
+		if inBounds[T](expected) {
+			if err != nil || toBig(got).Cmp(expected) != 0 {
+				t.Errorf("%T %s(%d, %d): got %d, %v, expected %s", a, op.name, a, b, got, err, expected)
+			}
+		} else if !errors.Is(err, ErrOverflow) {
+			t.Errorf("%T %s(%d, %d): expected ErrOverflow, got %d, %v", a, op.name, a, b, got, err)
+		}
+
+		if got := op.saturating(a, b); toBig(got).Cmp(clamp[T](expected)) != 0 {
+			t.Errorf("%T saturating %s(%d, %d): got %d, expected %s", a, op.name, a, b, got, clamp[T](expected))
+		}
+		if got := op.wrapping(a, b); toBig(got).Cmp(wrap[T](expected)) != 0 {
+			t.Errorf("%T wrapping %s(%d, %d): got %d, expected %s", a, op.name, a, b, got, wrap[T](expected))
+		}
+	}
+}
+
+func checkConvert[To, From Integer](t *testing.T, v From) {
+	t.Helper()
+	expected := toBig(v)
+	got, err := Convert[To](v)
+	if inBounds[To](expected) {
+		if err != nil || toBig(got).Cmp(expected) != 0 {
+			t.Errorf("%T(%d) to %T: got %d, %v", v, v, got, got, err)
+		}
+	} else if !errors.Is(err, ErrOverflow) {
+		t.Errorf("%T(%d) to %T: expected ErrOverflow, got %d, %v", v, v, got, got, err)
+	}
+	if got := SaturatingConvert[To](v); toBig(got).Cmp(clamp[To](expected)) != 0 {
+		t.Errorf("saturating %T(%d) to %T: got %d", v, v, got, got)
+	}
+	if got := WrappingConvert[To](v); toBig(got).Cmp(wrap[To](expected)) != 0 {
+		t.Errorf("wrapping %T(%d) to %T: got %d", v, v, got, got)
+	}
+}
+
+func TestBounds(t *testing.T) {
+	if min, max := Bounds[int8](); min != math.MinInt8 || max != math.MaxInt8 {
+		t.Errorf("int8: %d, %d", min, max)
+	}
+	if min, max := Bounds[int](); min != math.MinInt || max != math.MaxInt {
+		t.Errorf("int: %d, %d", min, max)
+	}
+	if min, max := Bounds[uint32](); min != 0 || max != math.MaxUint32 {
+		t.Errorf("uint32: %d, %d", min, max)
+	}
+}
+
+func FuzzInt8(f *testing.F) {
+	f.Add(int8(math.MaxInt8), int8(1))
+	f.Add(int8(math.MinInt8), int8(-1))
+	f.Add(int8(-128), int8(0))
+	f.Fuzz(func(t *testing.T, a, b int8) {
+		check(t, a, b)
+	})
+}
+
+func FuzzInt16(f *testing.F) {
+	f.Add(int16(math.MaxInt16), int16(2))
+	f.Add(int16(math.MinInt16), int16(-1))
+	f.Fuzz(func(t *testing.T, a, b int16) {
+		check(t, a, b)
+	})
+}
+
+func FuzzInt32(f *testing.F) {
+	f.Add(int32(math.MaxInt32), int32(1))
+	f.Add(int32(math.MinInt32), int32(-1))
+	f.Fuzz(func(t *testing.T, a, b int32) {
+		check(t, a, b)
+	})
+}
+
+func FuzzInt64(f *testing.F) {
+	f.Add(int64(math.MaxInt64), int64(1))
+	f.Add(int64(math.MinInt64), int64(-1))
+	f.Add(int64(math.MinInt64), int64(math.MinInt64))
+	f.Add(int64(1<<32), int64(1<<31))
+	f.Fuzz(func(t *testing.T, a, b int64) {
+		check(t, a, b)
+		check(t, int(a), int(b))
+	})
+}
+
+func FuzzUint8(f *testing.F) {
+	f.Add(uint8(math.MaxUint8), uint8(1))
+	f.Add(uint8(0), uint8(1))
+	f.Fuzz(func(t *testing.T, a, b uint8) {
+		check(t, a, b)
+	})
+}
+
+func FuzzUint16(f *testing.F) {
+	f.Add(uint16(math.MaxUint16), uint16(2))
+	f.Fuzz(func(t *testing.T, a, b uint16) {
+		check(t, a, b)
+	})
+}
+
+func FuzzUint32(f *testing.F) {
+	f.Add(uint32(math.MaxUint32), uint32(1))
+	f.Fuzz(func(t *testing.T, a, b uint32) {
+		check(t, a, b)
+	})
+}
+
+func FuzzUint64(f *testing.F) {
+	f.Add(uint64(math.MaxUint64), uint64(1))
+	f.Add(uint64(1<<32), uint64(1<<32))
+	f.Add(uint64(0), uint64(1))
+	f.Fuzz(func(t *testing.T, a, b uint64) {
+		check(t, a, b)
+		check(t, uint(a), uint(b))
+	})
+}
+
+func FuzzConvert(f *testing.F) {
+	f.Add(int64(-1))
+	f.Add(int64(math.MaxInt8 + 1))
+	f.Add(int64(math.MinInt32))
+	f.Add(int64(math.MaxInt64))
+	f.Fuzz(func(t *testing.T, v int64) {
+		checkConvert[int8](t, v)
+		checkConvert[int32](t, v)
+		checkConvert[uint8](t, v)
+		checkConvert[uint16](t, v)
+		checkConvert[uint64](t, v)
+		checkConvert[int64](t, uint64(v))
+		checkConvert[int16](t, uint64(v))
+		checkConvert[int8](t, int16(v))
+		checkConvert[uint32](t, int8(v))
+	})
+}
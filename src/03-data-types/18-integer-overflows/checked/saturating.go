@@ -0,0 +1,75 @@
+package checked
+
+func SaturatingAdd[T Integer](a, b T) T {
+	c, err := Add(a, b)
+	if err == nil {
+		return c
+	}
+	min, max := Bounds[T]()
+	if b < 0 {
+		return min
+	}
+	return max
+}
+
+func SaturatingSub[T Integer](a, b T) T {
+	c, err := Sub(a, b)
+	if err == nil {
+		return c
+	}
+	min, max := Bounds[T]()
+	if isSigned[T]() && b < 0 {
+		return max
+	}
+	return min
+}
+
+func SaturatingMul[T Integer](a, b T) T {
+	c, err := Mul(a, b)
+	if err == nil {
+		return c
+	}
+	min, max := Bounds[T]()
+	if (a < 0) != (b < 0) {
+		return min
+	}
+	return max
+}
+
+// SaturatingDiv panics if b is 0, like the / operator.
+func SaturatingDiv[T Integer](a, b T) T {
+	c, err := Div(a, b)
+	if err == ErrDivideByZero {
+		panic(err)
+	}
+	if err != nil {
+		// The only overflow is min / -1
+		_, max := Bounds[T]()
+		return max
+	}
+	return c
+}
+
+func SaturatingNeg[T Integer](a T) T {
+	c, err := Neg(a)
+	if err == nil {
+		return c
+	}
+	if isSigned[T]() {
+		_, max := Bounds[T]()
+		return max
+	}
+	return 0
+}
+
+func SaturatingConvert[To, From Integer](v From) To {
+	c, err := Convert[To](v)
+	if err == nil {
+		return c
+	}
+	min, max := Bounds[To]()
+	if v < 0 {
+		return min
+	}
+	return max
+}
@@ -0,0 +1,29 @@
+package checked
+
+// The wrapping variants behave like the Go operators. They exist to make
+// an intended wrap around explicit at the call site.
+
+func WrappingAdd[T Integer](a, b T) T {
+	return a + b
+}
+
+func WrappingSub[T Integer](a, b T) T {
+	return a - b
+}
+
+func WrappingMul[T Integer](a, b T) T {
+	return a * b
+}
+
+// WrappingDiv returns min for min / -1 and panics if b is 0.
+func WrappingDiv[T Integer](a, b T) T {
+	return a / b
+}
+
+func WrappingNeg[T Integer](a T) T {
+	return -a
+}
+
+func WrappingConvert[To, From Integer](v From) To {
+	return To(v)
+}
@@ -0,0 +1,90 @@
+package money
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type jsonMoney struct {
+	Amount   json.Number `json:"amount"`
+	Currency string      `json:"currency"`
+}
+
+// MarshalJSON encodes m as {"amount":"12.30","currency":"USD"}. The amount
+// is a string so that no decoder turns it into a float.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Amount   string `json:"amount"`
+		Currency string `json:"currency"`
+	}{
+		Amount:   m.Amount(),
+		Currency: m.currency.Code,
+	})
+}
+
+// UnmarshalJSON accepts the amount as a string or as a number. The number
+// is parsed from its text, not through a float.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	var v jsonMoney
+	dec := json.NewDecoder(strings.NewReader(string(data)))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err != nil {
+		return err
+	}
+	c, err := LookupCurrency(v.Currency)
+	if err != nil {
+		return err
+	}
+	parsed, err := Parse(v.Amount.String(), c)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// Value stores m as text, e.g. "12.30 USD".
+func (m Money) Value() (driver.Value, error) {
+	return m.String(), nil
+}
+
+// Scan reads the text written by Value. A bare number (text, integer or
+// float column) is also accepted if m already has a currency, for example
+// when scanning into Zero(USD).
+func (m *Money) Scan(src any) error {
+	var s string
+	switch v := src.(type) {
+	case string:
+		s = v
+	case []byte:
+		s = string(v)
+	case int64:
+		s = strconv.FormatInt(v, 10)
+	case float64:
+		s = strconv.FormatFloat(v, 'f', -1, 64)
+	case nil:
+		return fmt.Errorf("money: cannot scan NULL")
+	default:
+		return fmt.Errorf("money: cannot scan %T", src)
+	}
+
+	amount, code, hasCode := strings.Cut(strings.TrimSpace(s), " ")
+	c := m.currency
+	if hasCode {
+		var err error
+		if c, err = LookupCurrency(code); err != nil {
+			return err
+		}
+	} else if c.Code == "" {
+		return fmt.Errorf("money: no currency to scan %q", s)
+	}
+	parsed, err := Parse(amount, c)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
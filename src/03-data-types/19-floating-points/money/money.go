@@ -0,0 +1,281 @@
+// Package money provides a fixed-point amount type, avoiding the precision
+// loss of float32 and float64 balances.
+package money
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/teivah/100-go-mistakes/src/03-data-types/18-integer-overflows/checked"
+)
+
+var (
+	ErrCurrencyMismatch = errors.New("currency mismatch")
+	ErrUnknownCurrency  = errors.New("unknown currency")
+)
+
+// Currency is an ISO 4217 currency with its number of minor unit digits.
+type Currency struct {
+	Code     string
+	Exponent int
+}
+
+var (
+	EUR = Currency{Code: "EUR", Exponent: 2}
+	GBP = Currency{Code: "GBP", Exponent: 2}
+	USD = Currency{Code: "USD", Exponent: 2}
+	JPY = Currency{Code: "JPY", Exponent: 0}
+	BHD = Currency{Code: "BHD", Exponent: 3}
+)
+
+var currencies = map[string]Currency{
+	EUR.Code: EUR,
+	GBP.Code: GBP,
+	USD.Code: USD,
+	JPY.Code: JPY,
+	BHD.Code: BHD,
+}
+
+func LookupCurrency(code string) (Currency, error) {
+	c, ok := currencies[strings.ToUpper(code)]
+	if !ok {
+		return Currency{}, fmt.Errorf("%w: %q", ErrUnknownCurrency, code)
+	}
+	return c, nil
+}
+
+func (c Currency) scale() int64 {
+	scale := int64(1)
+	for i := 0; i < c.Exponent; i++ {
+		scale *= 10
+	}
+	return scale
+}
+
+// Money is an amount expressed as an integer number of minor units (e.g.
+// cents). The arithmetic is exact; the operations that can't be exact
+// round half to even (banker's rounding).
+type Money struct {
+	minor    int64
+	currency Currency
+}
+
+// Zero returns a zero amount, for example to Scan into.
+func Zero(c Currency) Money {
+	return Money{currency: c}
+}
+
+// FromMinor returns an amount of minor units, e.g. FromMinor(1050, USD) is
+// 10.50 USD.
+func FromMinor(minor int64, c Currency) Money {
+	return Money{minor: minor, currency: c}
+}
+
+// Parse parses a decimal amount like "-12.345". The digits beyond the
+// currency precision are rounded half to even.
+func Parse(s string, c Currency) (Money, error) {
+	s = strings.TrimSpace(s)
+	if !isDecimal(s) {
+		return Money{}, fmt.Errorf("invalid amount %q", s)
+	}
+	r, _ := new(big.Rat).SetString(s)
+	r.Mul(r, new(big.Rat).SetInt64(c.scale()))
+	minor, err := roundHalfEven(r.Num(), r.Denom())
+	if err != nil {
+		return Money{}, fmt.Errorf("amount %q: %w", s, err)
+	}
+	return Money{minor: minor, currency: c}, nil
+}
+
+// isDecimal reports whether s is like [+-]digits[.digits], rejecting the
+// fractions, exponents and base prefixes accepted by big.Rat.
+func isDecimal(s string) bool {
+	if s != "" && (s[0] == '-' || s[0] == '+') {
+		s = s[1:]
+	}
+	intPart, fracPart, hasDot := strings.Cut(s, ".")
+	if intPart == "" && fracPart == "" {
+		return false
+	}
+	if hasDot && fracPart == "" {
+		return false
+	}
+	for _, part := range []string{intPart, fracPart} {
+		for i := 0; i < len(part); i++ {
+			if part[i] < '0' || part[i] > '9' {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// FromFloat64 converts a float using its shortest decimal representation,
+// so that FromFloat64(0.1, USD) is exactly 0.10 USD.
+func FromFloat64(f float64, c Currency) (Money, error) {
+	return Parse(strconv.FormatFloat(f, 'f', -1, 64), c)
+}
+
+func (m Money) Currency() Currency { return m.currency }
+func (m Money) Minor() int64       { return m.minor }
+func (m Money) IsZero() bool       { return m.minor == 0 }
+func (m Money) IsNegative() bool   { return m.minor < 0 }
+
+func (m Money) sameCurrency(o Money) error {
+	if m.currency != o.currency {
+		return fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.currency.Code, o.currency.Code)
+	}
+	return nil
+}
+
+func (m Money) Add(o Money) (Money, error) {
+	if err := m.sameCurrency(o); err != nil {
+		return Money{}, err
+	}
+	minor, err := checked.Add(m.minor, o.minor)
+	if err != nil {
+		return Money{}, err
+	}
+	return Money{minor: minor, currency: m.currency}, nil
+}
+
+func (m Money) Sub(o Money) (Money, error) {
+	if err := m.sameCurrency(o); err != nil {
+		return Money{}, err
+	}
+	minor, err := checked.Sub(m.minor, o.minor)
+	if err != nil {
+		return Money{}, err
+	}
+	return Money{minor: minor, currency: m.currency}, nil
+}
+
+func (m Money) Neg() (Money, error) {
+	minor, err := checked.Neg(m.minor)
+	if err != nil {
+		return Money{}, err
+	}
+	return Money{minor: minor, currency: m.currency}, nil
+}
+
+func (m Money) MulInt(n int64) (Money, error) {
+	minor, err := checked.Mul(m.minor, n)
+	if err != nil {
+		return Money{}, err
+	}
+	return Money{minor: minor, currency: m.currency}, nil
+}
+
+// MulRatio returns m * num / den, for example to apply a rate of 1.5% with
+// MulRatio(15, 1000).
+func (m Money) MulRatio(num, den int64) (Money, error) {
+	if den == 0 {
+		return Money{}, checked.ErrDivideByZero
+	}
+	n := new(big.Int).Mul(big.NewInt(m.minor), big.NewInt(num))
+	minor, err := roundHalfEven(n, big.NewInt(den))
+	if err != nil {
+		return Money{}, err
+	}
+	return Money{minor: minor, currency: m.currency}, nil
+}
+
+// DivInt returns m / n.
+func (m Money) DivInt(n int64) (Money, error) {
+	return m.MulRatio(1, n)
+}
+
+// Cmp returns -1, 0 or +1 depending on whether m is less than, equal to or
+// greater than o.
+func (m Money) Cmp(o Money) (int, error) {
+	if err := m.sameCurrency(o); err != nil {
+		return 0, err
+	}
+	switch {
+	case m.minor < o.minor:
+		return -1, nil
+	case m.minor > o.minor:
+		return 1, nil
+	default:
+		return 0, nil
+	}
+}
+
+// Sum adds amounts of the same currency.
+func Sum(c Currency, amounts ...Money) (Money, error) {
+	total := Zero(c)
+	for _, m := range amounts {
+		var err error
+		if total, err = total.Add(m); err != nil {
+			return Money{}, err
+		}
+	}
+	return total, nil
+}
+
+// Average returns the average of amounts of the same currency.
+func Average(c Currency, amounts ...Money) (Money, error) {
+	if len(amounts) == 0 {
+		return Money{}, errors.New("no amount to average")
+	}
+	total, err := Sum(c, amounts...)
+	if err != nil {
+		return Money{}, err
+	}
+	return total.DivInt(int64(len(amounts)))
+}
+
+// Amount returns the decimal amount without the currency, e.g. "-12.30".
+func (m Money) Amount() string {
+	digits := strconv.FormatUint(abs(m.minor), 10)
+	exp := m.currency.Exponent
+	if len(digits) <= exp {
+		digits = strings.Repeat("0", exp-len(digits)+1) + digits
+	}
+	s := digits
+	if exp > 0 {
+		s = digits[:len(digits)-exp] + "." + digits[len(digits)-exp:]
+	}
+	if m.minor < 0 {
+		s = "-" + s
+	}
+	return s
+}
+
+func (m Money) String() string {
+	return m.Amount() + " " + m.currency.Code
+}
+
+func abs(v int64) uint64 {
+	if v < 0 {
+		return uint64(-(v + 1)) + 1
+	}
+	return uint64(v)
+}
+
+// roundHalfEven returns num/den rounded half to even.
+func roundHalfEven(num, den *big.Int) (int64, error) {
+	if den.Sign() < 0 {
+		num = new(big.Int).Neg(num)
+		den = new(big.Int).Neg(den)
+	}
+	q, r := new(big.Int).QuoRem(num, den, new(big.Int))
+	// Compare 2|r| to den to know whether the remainder is above half
+	twice := new(big.Int).Abs(r)
+	twice.Lsh(twice, 1)
+	c := twice.Cmp(den)
+	if c > 0 || (c == 0 && q.Bit(0) == 1) {
+		if num.Sign() < 0 {
+			q.Sub(q, big.NewInt(1))
+		} else {
+			q.Add(q, big.NewInt(1))
+		}
+	}
+	if !q.IsInt64() {
+		return 0, checked.ErrOverflow
+	}
+	return q.Int64(), nil
+}
@@ -0,0 +1,153 @@
+package money
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+
+	"github.com/teivah/100-go-mistakes/src/03-data-types/18-integer-overflows/checked"
+)
+
+func mustParse(t *testing.T, s string, c Currency) Money {
+	t.Helper()
+	m, err := Parse(s, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestParse(t *testing.T) {
+	tests := map[string]struct {
+		input    string
+		currency Currency
+		expected string
+	}{
+		`integer`:             {input: "12", currency: USD, expected: "12.00 USD"},
+		`negative`:            {input: "-0.5", currency: USD, expected: "-0.50 USD"},
+		`half to even down`:   {input: "0.125", currency: USD, expected: "0.12 USD"},
+		`half to even up`:     {input: "0.135", currency: USD, expected: "0.14 USD"},
+		`negative half`:       {input: "-0.125", currency: USD, expected: "-0.12 USD"},
+		`above half`:          {input: "0.1251", currency: USD, expected: "0.13 USD"},
+		`no minor unit`:       {input: "2.5", currency: JPY, expected: "2 JPY"},
+		`three digits`:        {input: ".0005", currency: BHD, expected: "0.000 BHD"},
+		`leading plus`:        {input: "+1.1", currency: EUR, expected: "1.10 EUR"},
+		`smaller than a unit`: {input: "0.01", currency: USD, expected: "0.01 USD"},
+	}
+	for name, tt := range tests {
+		if got := mustParse(t, tt.input, tt.currency).String(); got != tt.expected {
+			t.Errorf("%s: got %s, expected %s", name, got, tt.expected)
+		}
+	}
+
+	for _, input := range []string{"", "1/3", "1e3", "0x10", "1.", "-", "1.2.3"} {
+		if _, err := Parse(input, USD); err == nil {
+			t.Errorf("%q: expected an error", input)
+		}
+	}
+}
+
+func TestArithmetic(t *testing.T) {
+	// Adding 0.1 ten times is exactly 1, unlike with float64
+	total := Zero(USD)
+	tenCents, _ := FromFloat64(0.1, USD)
+	for i := 0; i < 10; i++ {
+		var err error
+		if total, err = total.Add(tenCents); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if total != mustParse(t, "1", USD) {
+		t.Errorf("got: %s", total)
+	}
+
+	avg, err := Average(USD, FromMinor(100, USD), FromMinor(300, USD), FromMinor(0, USD))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if avg.String() != "1.33 USD" {
+		t.Errorf("average: %s", avg)
+	}
+
+	fee, err := mustParse(t, "10.10", USD).MulRatio(15, 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 0.1515 rounds to 0.15
+	if fee.String() != "0.15 USD" {
+		t.Errorf("fee: %s", fee)
+	}
+
+	if _, err := FromMinor(1, USD).Add(FromMinor(1, EUR)); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Errorf("expected ErrCurrencyMismatch, got: %v", err)
+	}
+	if _, err := FromMinor(1<<62, USD).MulInt(4); !errors.Is(err, checked.ErrOverflow) {
+		t.Errorf("expected ErrOverflow, got: %v", err)
+	}
+	if _, err := FromMinor(1, USD).DivInt(0); !errors.Is(err, checked.ErrDivideByZero) {
+		t.Errorf("expected ErrDivideByZero, got: %v", err)
+	}
+}
+
+func TestJSON(t *testing.T) {
+	type customer struct {
+		ID      string `json:"id"`
+		Balance Money  `json:"balance"`
+	}
+	data, err := json.Marshal(customer{ID: "1", Balance: mustParse(t, "-10.5", EUR)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"id":"1","balance":{"amount":"-10.50","currency":"EUR"}}`
+	if string(data) != expected {
+		t.Errorf("got: %s", data)
+	}
+
+	var c customer
+	if err := json.Unmarshal([]byte(`{"balance":{"amount":0.30000000000000004,"currency":"usd"}}`), &c); err != nil {
+		t.Fatal(err)
+	}
+	if c.Balance.String() != "0.30 USD" {
+		t.Errorf("got: %s", c.Balance)
+	}
+	if err := json.Unmarshal([]byte(`{"balance":{"amount":"1","currency":"XXX"}}`), &c); !errors.Is(err, ErrUnknownCurrency) {
+		t.Errorf("expected ErrUnknownCurrency, got: %v", err)
+	}
+}
+
+func TestSQL(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.SetMaxOpenConns(1)
+	defer db.Close()
+
+	if _, err := db.Exec(`CREATE TABLE customers (id TEXT, balance TEXT, legacy REAL)`); err != nil {
+		t.Fatal(err)
+	}
+	balance := mustParse(t, "1234.56", GBP)
+	if _, err := db.Exec(`INSERT INTO customers VALUES (?, ?, ?)`, "1", balance, 0.1); err != nil {
+		t.Fatal(err)
+	}
+
+	var got Money
+	legacy := Zero(USD)
+	if err := db.QueryRow(`SELECT balance, legacy FROM customers`).Scan(&got, &legacy); err != nil {
+		t.Fatal(err)
+	}
+	if got != balance {
+		t.Errorf("balance: got %s", got)
+	}
+	if legacy.String() != "0.10 USD" {
+		t.Errorf("legacy: got %s", legacy)
+	}
+
+	var noCurrency Money
+	if err := db.QueryRow(`SELECT legacy FROM customers`).Scan(&noCurrency); err == nil {
+		t.Error("expected an error without currency")
+	}
+}
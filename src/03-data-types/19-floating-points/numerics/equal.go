@@ -0,0 +1,97 @@
+package numerics
+
+import "math"
+
+type Class int
+
+const (
+	Finite Class = iota
+	PositiveInf
+	NegativeInf
+	NaN
+)
+
+func (c Class) String() string {
+	switch c {
+	case Finite:
+		return "finite"
+	case PositiveInf:
+		return "+Inf"
+	case NegativeInf:
+		return "-Inf"
+	default:
+		return "NaN"
+	}
+}
+
+func Classify(x float64) Class {
+	switch {
+	case math.IsNaN(x):
+		return NaN
+	case math.IsInf(x, 1):
+		return PositiveInf
+	case math.IsInf(x, -1):
+		return NegativeInf
+	default:
+		return Finite
+	}
+}
+
+// special reports how to compare a and b if one of them isn't finite. NaN
+// is never equal to anything, and an infinity is only equal to itself.
+func special(a, b float64) (equal, handled bool) {
+	ca, cb := Classify(a), Classify(b)
+	if ca == Finite && cb == Finite {
+		return false, false
+	}
+	return ca == cb && ca != NaN, true
+}
+
+// ordered maps a float64 to an integer whose order matches the order of the
+// floats; hence, adjacent floats map to adjacent integers.
+func ordered(x float64) int64 {
+	bits := int64(math.Float64bits(x))
+	if bits < 0 {
+		// Negative floats are stored as sign and magnitude
+		return math.MinInt64 - bits
+	}
+	return bits
+}
+
+// ULPDistance returns the number of representable float64 values between a
+// and b. +0 and -0 are 0 ULP apart. It returns math.MaxUint64 if a or b
+// isn't finite, unless they are the same infinity.
+func ULPDistance(a, b float64) uint64 {
+	if equal, handled := special(a, b); handled {
+		if equal {
+			return 0
+		}
+		return math.MaxUint64
+	}
+	ia, ib := ordered(a), ordered(b)
+	if ia > ib {
+		ia, ib = ib, ia
+	}
+	return uint64(ib) - uint64(ia)
+}
+
+// EqualULP reports whether a and b are at most maxULP representable values
+// apart. It suits values of any magnitude, except near 0 where EqualEpsilon
+// should be preferred.
+func EqualULP(a, b float64, maxULP uint64) bool {
+	return ULPDistance(a, b) <= maxULP
+}
+
+// EqualEpsilon reports whether |a-b| <= absTol or |a-b| <= relTol*max(|a|,|b|).
+// The absolute tolerance handles the values near 0, where a relative one is
+// meaningless.
+func EqualEpsilon(a, b, absTol, relTol float64) bool {
+	if equal, handled := special(a, b); handled {
+		return equal
+	}
+	diff := math.Abs(a - b)
+	if diff <= absTol {
+		return true
+	}
+	return diff <= relTol*math.Max(math.Abs(a), math.Abs(b))
+}
@@ -0,0 +1,163 @@
+package numerics
+
+import (
+	"math"
+	"math/big"
+	"math/rand"
+	"testing"
+)
+
+// exactSum returns the correctly rounded sum using arbitrary precision.
+func exactSum(xs []float64) float64 {
+	sum := new(big.Float).SetPrec(4096)
+	for _, x := range xs {
+		sum.Add(sum, new(big.Float).SetPrec(4096).SetFloat64(x))
+	}
+	f, _ := sum.Float64()
+	return f
+}
+
+// f1Values are the values added by f1: 10,000 then n times 1.0001.
+func f1Values(n int) []float64 {
+	xs := make([]float64, 0, n+1)
+	xs = append(xs, 10_000.)
+	for i := 0; i < n; i++ {
+		xs = append(xs, 1.0001)
+	}
+	return xs
+}
+
+func randomValues(n int) []float64 {
+	r := rand.New(rand.NewSource(42))
+	xs := make([]float64, n)
+	for i := range xs {
+		// Mix magnitudes and signs to trigger cancellations
+		xs[i] = (r.Float64() - 0.5) * math.Pow(10, float64(r.Intn(20)-10))
+	}
+	return xs
+}
+
+func TestSumAccuracy(t *testing.T) {
+	inputs := map[string][]float64{
+		"f1":     f1Values(1_000_000),
+		"random": randomValues(100_000),
+	}
+	for name, xs := range inputs {
+		xs := xs
+		t.Run(name, func(t *testing.T) {
+			exact := exactSum(xs)
+			naiveErr := math.Abs(NaiveSum(xs) - exact)
+
+			if got := NeumaierSum(xs); !EqualULP(got, exact, 1) {
+				t.Errorf("neumaier: got %v, expected %v", got, exact)
+			}
+			if got := KahanSum(xs); math.Abs(got-exact) > naiveErr {
+				t.Errorf("kahan: error %v larger than naive %v", math.Abs(got-exact), naiveErr)
+			}
+			if got := PairwiseSum(xs); math.Abs(got-exact) > naiveErr {
+				t.Errorf("pairwise: error %v larger than naive %v", math.Abs(got-exact), naiveErr)
+			}
+		})
+	}
+}
+
+func TestNeumaierSum_LargeValue(t *testing.T) {
+	// Kahan loses the 1s, Neumaier doesn't
+	xs := []float64{1, 1e100, 1, -1e100}
+	if got := NeumaierSum(xs); got != 2 {
+		t.Errorf("got: %v", got)
+	}
+}
+
+func TestSum_Specials(t *testing.T) {
+	var a float64
+	positiveInf := 1 / a
+	negativeInf := -1 / a
+	nan := a / a
+
+	tests := map[string]struct {
+		xs       []float64
+		expected Class
+	}{
+		`+Inf`:        {xs: []float64{1, positiveInf, 2}, expected: PositiveInf},
+		`-Inf`:        {xs: []float64{negativeInf, 1}, expected: NegativeInf},
+		`+Inf + -Inf`: {xs: []float64{positiveInf, negativeInf}, expected: NaN},
+		`NaN`:         {xs: []float64{1, nan}, expected: NaN},
+		`finite`:      {xs: []float64{1, 2}, expected: Finite},
+		`overflow`:    {xs: []float64{math.MaxFloat64, math.MaxFloat64, 1}, expected: PositiveInf},
+		`-overflow`:   {xs: []float64{-math.MaxFloat64, -math.MaxFloat64}, expected: NegativeInf},
+	}
+	for name, tt := range tests {
+		if got := Classify(NeumaierSum(tt.xs)); got != tt.expected {
+			t.Errorf("%s: got %v, expected %v", name, got, tt.expected)
+		}
+		// The naive and pairwise sums agree on the special values
+		if got := Classify(NaiveSum(tt.xs)); got != tt.expected {
+			t.Errorf("%s: naive sum: got %v, expected %v", name, got, tt.expected)
+		}
+		if got := Classify(PairwiseSum(tt.xs)); got != tt.expected {
+			t.Errorf("%s: pairwise sum: got %v, expected %v", name, got, tt.expected)
+		}
+	}
+}
+
+func TestULPDistance(t *testing.T) {
+	next := math.Nextafter(1, 2)
+	if d := ULPDistance(1, next); d != 1 {
+		t.Errorf("1, next: %d", d)
+	}
+	tiny := math.SmallestNonzeroFloat64
+	if d := ULPDistance(-tiny, tiny); d != 2 {
+		t.Errorf("-tiny, tiny: %d", d)
+	}
+	if d := ULPDistance(0, math.Copysign(0, -1)); d != 0 {
+		t.Errorf("+0, -0: %d", d)
+	}
+	if d := ULPDistance(math.Inf(1), math.Inf(1)); d != 0 {
+		t.Errorf("+Inf, +Inf: %d", d)
+	}
+	if d := ULPDistance(math.NaN(), math.NaN()); d != math.MaxUint64 {
+		t.Errorf("NaN, NaN: %d", d)
+	}
+	if EqualULP(math.MaxFloat64, math.Inf(1), 1) {
+		t.Error("max float is not equal to +Inf")
+	}
+}
+
+func TestEqualEpsilon(t *testing.T) {
+	if !EqualEpsilon(0.1+0.2, 0.3, 0, 1e-15) {
+		t.Error("0.1+0.2 should be close to 0.3")
+	}
+	if EqualEpsilon(1e-20, 0, 0, 1e-9) {
+		t.Error("a relative tolerance can't match 0")
+	}
+	if !EqualEpsilon(1e-20, 0, 1e-12, 1e-9) {
+		t.Error("the absolute tolerance should match 0")
+	}
+	if EqualEpsilon(math.NaN(), math.NaN(), 1, 1) {
+		t.Error("NaN is never equal")
+	}
+	if !EqualEpsilon(math.Inf(-1), math.Inf(-1), 0, 0) {
+		t.Error("-Inf equals itself")
+	}
+	if EqualEpsilon(math.Inf(1), math.MaxFloat64, 1, 1) {
+		t.Error("+Inf is not close to a finite value")
+	}
+}
+
+var global float64
+
+func benchmarkSum(b *testing.B, sum func([]float64) float64) {
+	xs := randomValues(100_000)
+	b.ResetTimer()
+	var local float64
+	for i := 0; i < b.N; i++ {
+		local = sum(xs)
+	}
+	global = local
+}
+
+func BenchmarkNaiveSum(b *testing.B)    { benchmarkSum(b, NaiveSum) }
+func BenchmarkKahanSum(b *testing.B)    { benchmarkSum(b, KahanSum) }
+func BenchmarkNeumaierSum(b *testing.B) { benchmarkSum(b, NeumaierSum) }
+func BenchmarkPairwiseSum(b *testing.B) { benchmarkSum(b, PairwiseSum) }
@@ -0,0 +1,101 @@
+// Package numerics provides floating-point summation and comparison
+// helpers reducing the accumulated errors shown by f1 and f2.
+package numerics
+
+import "math"
+
+// Accumulator sums values using the Neumaier variant of the Kahan
+// compensated summation. Infinities and NaN are tracked apart so that they
+// don't corrupt the compensation term. The zero value is ready to use.
+type Accumulator struct {
+	sum          float64
+	compensation float64
+	posInf       bool
+	negInf       bool
+	nan          bool
+}
+
+func (a *Accumulator) Add(x float64) {
+	switch {
+	case math.IsNaN(x):
+		a.nan = true
+		return
+	case math.IsInf(x, 1):
+		a.posInf = true
+		return
+	case math.IsInf(x, -1):
+		a.negInf = true
+		return
+	}
+
+	t := a.sum + x
+	if math.IsInf(t, 0) {
+		// Overflow of finite values: the compensation would become NaN
+		a.Add(t)
+		return
+	}
+	if math.Abs(a.sum) >= math.Abs(x) {
+		a.compensation += (a.sum - t) + x
+	} else {
+		a.compensation += (x - t) + a.sum
+	}
+	a.sum = t
+}
+
+// Sum returns NaN if a NaN was added or if both infinities were added, and
+// the infinity added otherwise.
+func (a *Accumulator) Sum() float64 {
+	switch {
+	case a.nan || (a.posInf && a.negInf):
+		return math.NaN()
+	case a.posInf:
+		return math.Inf(1)
+	case a.negInf:
+		return math.Inf(-1)
+	}
+	return a.sum + a.compensation
+}
+
+// NaiveSum adds the values in order, like f1 and f2.
+func NaiveSum(xs []float64) float64 {
+	sum := 0.
+	for _, x := range xs {
+		sum += x
+	}
+	return sum
+}
+
+// KahanSum uses the original Kahan algorithm. It loses its compensation
+// when a value is larger than the running sum; NeumaierSum doesn't.
+func KahanSum(xs []float64) float64 {
+	var sum, compensation float64
+	for _, x := range xs {
+		y := x - compensation
+		t := sum + y
+		compensation = (t - sum) - y
+		sum = t
+	}
+	return sum
+}
+
+func NeumaierSum(xs []float64) float64 {
+	var a Accumulator
+	for _, x := range xs {
+		a.Add(x)
+	}
+	return a.Sum()
+}
+
+// pairwiseBlock is the size under which PairwiseSum sums naively.
+const pairwiseBlock = 128
+
+// PairwiseSum splits the values recursively in halves. Its error grows in
+// O(log n) instead of O(n) for the naive summation, without the cost of a
+// compensation term.
+func PairwiseSum(xs []float64) float64 {
+	if len(xs) <= pairwiseBlock {
+		return NaiveSum(xs)
+	}
+	m := len(xs) / 2
+	return PairwiseSum(xs[:m]) + PairwiseSum(xs[m:])
+}
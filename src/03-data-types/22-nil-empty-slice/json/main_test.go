@@ -0,0 +1,57 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/teivah/100-go-mistakes/src/03-data-types/22-nil-empty-slice/json/slicejson"
+)
+
+func TestCustomer_Policies(t *testing.T) {
+	customers := []customer{
+		{ID: "foo", Operations: nil},
+		{ID: "bar", Operations: make([]float32, 0)},
+		{ID: "baz", Operations: []float32{1.5}},
+	}
+	tests := []struct {
+		policy   slicejson.Policy
+		expected []string
+	}{
+		{
+			policy: slicejson.Preserve,
+			expected: []string{
+				`{"ID":"foo","Operations":null}`,
+				`{"ID":"bar","Operations":[]}`,
+				`{"ID":"baz","Operations":[1.5]}`,
+			},
+		},
+		{
+			policy: slicejson.Empty,
+			expected: []string{
+				`{"ID":"foo","Operations":[]}`,
+				`{"ID":"bar","Operations":[]}`,
+				`{"ID":"baz","Operations":[1.5]}`,
+			},
+		},
+		{
+			policy: slicejson.Omit,
+			expected: []string{
+				`{"ID":"foo"}`,
+				`{"ID":"bar"}`,
+				`{"ID":"baz","Operations":[1.5]}`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.policy.String(), func(t *testing.T) {
+			for i, c := range customers {
+				b, err := slicejson.Marshal(c, tt.policy)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if string(b) != tt.expected[i] {
+					t.Errorf("got: %s, expected: %s", b, tt.expected[i])
+				}
+			}
+		})
+	}
+}
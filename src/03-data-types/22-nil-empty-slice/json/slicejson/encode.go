@@ -0,0 +1,399 @@
+// Package slicejson encodes values to JSON like encoding/json, except that
+// nil and empty slices follow a single Policy for the whole value.
+//
+// The struct tags name, omitempty and "-" are supported, and so are the
+// json.Marshaler and encoding.TextMarshaler implementations; the policy
+// doesn't apply to what they return. Embedded structs are flattened, but
+// without the conflict resolution of encoding/json.
+package slicejson
+
+import (
+	"bufio"
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Policy defines how nil and empty slices are encoded.
+type Policy int
+
+const (
+	// Preserve encodes nil slices as null and empty slices as [], like
+	// encoding/json.
+	Preserve Policy = iota
+	// Empty encodes nil slices as [], and nil byte slices, encoded as base64
+	// strings, as "".
+	Empty
+	// Omit skips the struct fields and map entries holding a nil or empty
+	// slice. Elsewhere, e.g. in an array, such a slice is encoded as [].
+	Omit
+)
+
+func (p Policy) String() string {
+	switch p {
+	case Preserve:
+		return "preserve"
+	case Empty:
+		return "empty"
+	case Omit:
+		return "omit"
+	}
+	return "Policy(" + strconv.Itoa(int(p)) + ")"
+}
+
+// maxDepth bounds the nesting, which is only reached with cyclic values.
+const maxDepth = 1000
+
+var (
+	marshalerType     = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// Encoder writes JSON values to an output stream.
+type Encoder struct {
+	w      *bufio.Writer
+	policy Policy
+}
+
+func NewEncoder(w io.Writer, policy Policy) *Encoder {
+	return &Encoder{w: bufio.NewWriter(w), policy: policy}
+}
+
+// Encode writes the JSON encoding of v followed by a newline. The output is
+// flushed even if an error occurs, so a partial value may have been written.
+func (e *Encoder) Encode(v any) error {
+	err := e.encode(reflect.ValueOf(v), 0)
+	if err == nil {
+		err = e.w.WriteByte('\n')
+	}
+	if ferr := e.w.Flush(); err == nil {
+		err = ferr
+	}
+	return err
+}
+
+// Marshal returns the JSON encoding of v.
+func Marshal(v any, policy Policy) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := NewEncoder(&buf, policy).Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// Value wraps a value so that it implements json.Marshaler with a policy,
+// e.g. to be embedded in a structure encoded with encoding/json.
+type Value struct {
+	V      any
+	Policy Policy
+}
+
+func (v Value) MarshalJSON() ([]byte, error) {
+	return Marshal(v.V, v.Policy)
+}
+
+func (e *Encoder) encode(v reflect.Value, depth int) error {
+	if depth > maxDepth {
+		return errors.New("slicejson: maximum depth exceeded, is the value cyclic?")
+	}
+	if !v.IsValid() {
+		_, err := e.w.WriteString("null")
+		return err
+	}
+	if ok, err := e.encodeMarshaler(v); ok {
+		return err
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		_, err := e.w.WriteString(strconv.FormatBool(v.Bool()))
+		return err
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		_, err := e.w.WriteString(strconv.FormatInt(v.Int(), 10))
+		return err
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		_, err := e.w.WriteString(strconv.FormatUint(v.Uint(), 10))
+		return err
+	case reflect.Float32:
+		return e.writeJSON(float32(v.Float()))
+	case reflect.Float64:
+		return e.writeJSON(v.Float())
+	case reflect.String:
+		return e.writeJSON(v.String())
+	case reflect.Interface, reflect.Pointer:
+		if v.IsNil() {
+			_, err := e.w.WriteString("null")
+			return err
+		}
+		return e.encode(v.Elem(), depth+1)
+	case reflect.Struct:
+		return e.encodeStruct(v, depth)
+	case reflect.Map:
+		return e.encodeMap(v, depth)
+	case reflect.Slice:
+		if v.IsNil() && e.policy == Preserve {
+			_, err := e.w.WriteString("null")
+			return err
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			b := v.Bytes()
+			if b == nil {
+				// Past the Preserve check: encoded as "" rather than null
+				b = []byte{}
+			}
+			return e.writeJSON(b)
+		}
+		return e.encodeArray(v, depth)
+	case reflect.Array:
+		return e.encodeArray(v, depth)
+	}
+	return fmt.Errorf("slicejson: unsupported type %s", v.Type())
+}
+
+// encodeMarshaler delegates to json.Marshaler or encoding.TextMarshaler if v
+// implements one of them.
+func (e *Encoder) encodeMarshaler(v reflect.Value) (bool, error) {
+	if v.Kind() != reflect.Pointer && v.CanAddr() &&
+		(reflect.PointerTo(v.Type()).Implements(marshalerType) ||
+			reflect.PointerTo(v.Type()).Implements(textMarshalerType)) {
+		v = v.Addr()
+	}
+	if !v.CanInterface() {
+		return false, nil
+	}
+	if (v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface) && v.IsNil() {
+		return false, nil
+	}
+	switch m := v.Interface().(type) {
+	case json.Marshaler:
+		if v.Kind() == reflect.Interface {
+			return false, nil
+		}
+		b, err := m.MarshalJSON()
+		if err != nil {
+			return true, fmt.Errorf("slicejson: calling MarshalJSON for type %s: %w", v.Type(), err)
+		}
+		var buf bytes.Buffer
+		if err := json.Compact(&buf, b); err != nil {
+			return true, fmt.Errorf("slicejson: invalid JSON from MarshalJSON for type %s: %w", v.Type(), err)
+		}
+		_, err = e.w.Write(buf.Bytes())
+		return true, err
+	case encoding.TextMarshaler:
+		b, err := m.MarshalText()
+		if err != nil {
+			return true, fmt.Errorf("slicejson: calling MarshalText for type %s: %w", v.Type(), err)
+		}
+		return true, e.writeJSON(string(b))
+	}
+	return false, nil
+}
+
+func (e *Encoder) writeJSON(v any) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("slicejson: %w", err)
+	}
+	_, err = e.w.Write(b)
+	return err
+}
+
+func (e *Encoder) encodeArray(v reflect.Value, depth int) error {
+	if err := e.w.WriteByte('['); err != nil {
+		return err
+	}
+	for i := 0; i < v.Len(); i++ {
+		if i > 0 {
+			if err := e.w.WriteByte(','); err != nil {
+				return err
+			}
+		}
+		if err := e.encode(v.Index(i), depth+1); err != nil {
+			return err
+		}
+	}
+	return e.w.WriteByte(']')
+}
+
+type field struct {
+	name      string
+	index     []int
+	omitEmpty bool
+}
+
+// fields returns the encoded fields of a struct type, in declaration order.
+func fields(t reflect.Type, index []int) []field {
+	var fs []field
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		idx := append(append([]int(nil), index...), i)
+
+		ft := f.Type
+		if ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		if f.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+			fs = append(fs, fields(ft, idx)...)
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fs = append(fs, field{
+			name:      name,
+			index:     idx,
+			omitEmpty: strings.Contains(","+opts+",", ",omitempty,"),
+		})
+	}
+	return fs
+}
+
+// fieldByIndex is like reflect.Value.FieldByIndex but reports false instead
+// of panicking on a nil embedded pointer.
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
+
+func (e *Encoder) encodeStruct(v reflect.Value, depth int) error {
+	if err := e.w.WriteByte('{'); err != nil {
+		return err
+	}
+	first := true
+	for _, f := range fields(v.Type(), nil) {
+		fv, ok := fieldByIndex(v, f.index)
+		if !ok || (f.omitEmpty && isEmpty(fv)) || e.omitted(fv) {
+			continue
+		}
+		if err := e.writeKey(f.name, first); err != nil {
+			return err
+		}
+		first = false
+		if err := e.encode(fv, depth+1); err != nil {
+			return err
+		}
+	}
+	return e.w.WriteByte('}')
+}
+
+func (e *Encoder) encodeMap(v reflect.Value, depth int) error {
+	if v.IsNil() {
+		_, err := e.w.WriteString("null")
+		return err
+	}
+
+	type entry struct {
+		key   string
+		value reflect.Value
+	}
+	entries := make([]entry, 0, v.Len())
+	iter := v.MapRange()
+	for iter.Next() {
+		if e.omitted(iter.Value()) {
+			continue
+		}
+		key, err := mapKey(iter.Key())
+		if err != nil {
+			return err
+		}
+		entries = append(entries, entry{key: key, value: iter.Value()})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].key < entries[j].key
+	})
+
+	if err := e.w.WriteByte('{'); err != nil {
+		return err
+	}
+	for i, entry := range entries {
+		if err := e.writeKey(entry.key, i == 0); err != nil {
+			return err
+		}
+		if err := e.encode(entry.value, depth+1); err != nil {
+			return err
+		}
+	}
+	return e.w.WriteByte('}')
+}
+
+func mapKey(k reflect.Value) (string, error) {
+	if k.Kind() == reflect.String {
+		return k.String(), nil
+	}
+	if m, ok := k.Interface().(encoding.TextMarshaler); ok {
+		if k.Kind() == reflect.Pointer && k.IsNil() {
+			return "", nil
+		}
+		b, err := m.MarshalText()
+		return string(b), err
+	}
+	switch k.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(k.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(k.Uint(), 10), nil
+	}
+	return "", fmt.Errorf("slicejson: unsupported map key type %s", k.Type())
+}
+
+func (e *Encoder) writeKey(key string, first bool) error {
+	if !first {
+		if err := e.w.WriteByte(','); err != nil {
+			return err
+		}
+	}
+	if err := e.writeJSON(key); err != nil {
+		return err
+	}
+	return e.w.WriteByte(':')
+}
+
+// omitted reports whether v is a slice to skip under the Omit policy.
+func (e *Encoder) omitted(v reflect.Value) bool {
+	for v.Kind() == reflect.Interface && !v.IsNil() {
+		v = v.Elem()
+	}
+	return e.policy == Omit && v.Kind() == reflect.Slice && v.Len() == 0
+}
+
+// isEmpty follows the omitempty definition of encoding/json.
+func isEmpty(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Pointer:
+		return v.IsNil()
+	}
+	return false
+}
@@ -0,0 +1,186 @@
+package slicejson
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+type inner struct {
+	Values []int
+	Tags   map[string][]string
+}
+
+type embedded struct {
+	Embedded []string `json:"embedded"`
+}
+
+type outer struct {
+	embedded
+	Name     string `json:"name"`
+	Skipped  string `json:"-"`
+	Optional string `json:"optional,omitempty"`
+	Inner    inner
+	Pointer  *inner
+	Any      any
+	Nested   [][]int
+	Bytes    []byte
+	Time     time.Time
+	private  []int
+}
+
+func TestMarshal_PreserveMatchesEncodingJSON(t *testing.T) {
+	values := []any{
+		nil,
+		42,
+		-1.5,
+		float32(0.1),
+		"<html> & \"quotes\"",
+		[]int(nil),
+		[]int{},
+		map[int]string{2: "b", 1: "a"},
+		outer{},
+		outer{
+			embedded: embedded{Embedded: []string{}},
+			Name:     "foo",
+			Inner:    inner{Values: []int{1}, Tags: map[string][]string{"a": nil, "b": {}}},
+			Pointer:  &inner{},
+			Any:      []string(nil),
+			Nested:   [][]int{nil, {}, {1}},
+			Bytes:    []byte("bar"),
+			Time:     time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC),
+			private:  []int{1},
+		},
+	}
+	for _, v := range values {
+		expected, err := json.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := Marshal(v, Preserve)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, expected) {
+			t.Errorf("%#v:\ngot:      %s\nexpected: %s", v, got, expected)
+		}
+	}
+}
+
+func TestMarshal_Policies(t *testing.T) {
+	v := map[string]any{
+		"nil":    []int(nil),
+		"empty":  []int{},
+		"filled": []int{1},
+		"inner": inner{
+			Tags: map[string][]string{"a": nil},
+		},
+		"array": [2][]int{},
+	}
+	tests := []struct {
+		policy   Policy
+		expected string
+	}{
+		{
+			policy:   Preserve,
+			expected: `{"array":[null,null],"empty":[],"filled":[1],"inner":{"Values":null,"Tags":{"a":null}},"nil":null}`,
+		},
+		{
+			policy:   Empty,
+			expected: `{"array":[[],[]],"empty":[],"filled":[1],"inner":{"Values":[],"Tags":{"a":[]}},"nil":[]}`,
+		},
+		{
+			policy:   Omit,
+			expected: `{"array":[[],[]],"filled":[1],"inner":{"Tags":{}}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.policy.String(), func(t *testing.T) {
+			got, err := Marshal(v, tt.policy)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.expected {
+				t.Errorf("got:      %s\nexpected: %s", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestEncoder_Stream(t *testing.T) {
+	var buf bytes.Buffer
+	enc := NewEncoder(&buf, Empty)
+	for _, v := range []any{[]int(nil), inner{}} {
+		if err := enc.Encode(v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expected := "[]\n" + `{"Values":[],"Tags":null}` + "\n"
+	if buf.String() != expected {
+		t.Errorf("got: %q", buf.String())
+	}
+}
+
+type failingMarshaler struct{}
+
+func (failingMarshaler) MarshalJSON() ([]byte, error) {
+	return nil, errors.New("foo")
+}
+
+type cyclic struct {
+	Next *cyclic
+}
+
+func TestEncoder_Errors(t *testing.T) {
+	c := &cyclic{}
+	c.Next = c
+	values := map[string]any{
+		"marshaler": []any{failingMarshaler{}},
+		"cycle":     c,
+		"channel":   make(chan int),
+		"map key":   map[float64]int{1: 1},
+	}
+	for name, v := range values {
+		t.Run(name, func(t *testing.T) {
+			_, err := Marshal(v, Empty)
+			if err == nil || !strings.HasPrefix(err.Error(), "slicejson:") {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestValue_MarshalJSON(t *testing.T) {
+	b, err := json.Marshal(struct {
+		Payload Value
+	}{
+		Payload: Value{V: inner{}, Policy: Omit},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := `{"Payload":{"Tags":null}}`; string(b) != expected {
+		t.Errorf("got: %s", b)
+	}
+}
+
+func TestMarshal_NilBytesField(t *testing.T) {
+	v := struct{ Bytes []byte }{}
+	tests := map[Policy]string{
+		Preserve: `{"Bytes":null}`,
+		Empty:    `{"Bytes":""}`,
+		Omit:     `{}`,
+	}
+	for policy, expected := range tests {
+		got, err := Marshal(v, policy)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != expected {
+			t.Errorf("%v: got %s, expected %s", policy, got, expected)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package sliceutil
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+	"unsafe"
+)
+
+// Retention describes a slice pinning more memory than it uses.
+type Retention struct {
+	Len int
+	// Cap is the number of elements of the backing array kept alive.
+	Cap         int
+	PinnedBytes uintptr
+	UsedBytes   uintptr
+	// Caller is the file:line of the call to Sub or Check.
+	Caller string
+}
+
+func (r Retention) String() string {
+	return fmt.Sprintf("%s: slice of %d elements pins an array of %d (%d of %d bytes used)",
+		r.Caller, r.Len, r.Cap, r.UsedBytes, r.PinnedBytes)
+}
+
+var debug struct {
+	mu        sync.RWMutex
+	enabled   bool
+	threshold int
+	report    func(Retention)
+}
+
+// EnableDebug makes Sub and Check call report when a slice keeps more than
+// threshold times its length alive. It's meant for tests and debugging as
+// it costs a runtime.Caller call per report.
+func EnableDebug(threshold int, report func(Retention)) {
+	debug.mu.Lock()
+	defer debug.mu.Unlock()
+	debug.enabled = true
+	debug.threshold = threshold
+	debug.report = report
+}
+
+func DisableDebug() {
+	debug.mu.Lock()
+	defer debug.mu.Unlock()
+	debug.enabled = false
+	debug.report = nil
+}
+
+// Sub returns s[i:j]. In debug mode, it reports if the result pins much more
+// than it uses: the whole array of s is retained, including the elements
+// before i that cap(s[i:j]) doesn't show.
+func Sub[T any](s []T, i, j int) []T {
+	res := s[i:j]
+	check(len(res), cap(s), elemSize[T]())
+	return res
+}
+
+// Check returns s unchanged. In debug mode, it reports if cap(s) is much
+// larger than len(s), for example before storing s for a long time.
+func Check[T any](s []T) []T {
+	check(len(s), cap(s), elemSize[T]())
+	return s
+}
+
+func elemSize[T any]() uintptr {
+	var zero T
+	return unsafe.Sizeof(zero)
+}
+
+func check(length, capacity int, size uintptr) {
+	debug.mu.RLock()
+	enabled, threshold, report := debug.enabled, debug.threshold, debug.report
+	debug.mu.RUnlock()
+	if !enabled || capacity <= threshold*length || capacity == 0 {
+		return
+	}
+
+	caller := "unknown"
+	// Skip check and Sub or Check
+	if _, file, line, ok := runtime.Caller(2); ok {
+		caller = fmt.Sprintf("%s:%d", file, line)
+	}
+	report(Retention{
+		Len:         length,
+		Cap:         capacity,
+		PinnedBytes: uintptr(capacity) * size,
+		UsedBytes:   uintptr(length) * size,
+		Caller:      caller,
+	})
+}
@@ -0,0 +1,71 @@
+// Package sliceutil provides slice helpers avoiding the memory leaks caused
+// by sub-slices retaining large backing arrays.
+package sliceutil
+
+// Clip removes the spare capacity of s so that appending to the result
+// allocates a new array instead of overwriting the elements after len(s).
+// The backing array is still retained; use CloneN or Bound to release it.
+func Clip[T any](s []T) []T {
+	return s[:len(s):len(s)]
+}
+
+// CloneN returns a copy of the first n elements of s (or all of them if s is
+// shorter) in a new array of capacity n. Keeping the result doesn't retain
+// the backing array of s.
+func CloneN[T any](s []T, n int) []T {
+	if n > len(s) {
+		n = len(s)
+	}
+	if n < 0 {
+		n = 0
+	}
+	res := make([]T, n)
+	copy(res, s)
+	return res
+}
+
+// Bound returns s itself if its capacity is at most maxCap, and otherwise a
+// copy whose capacity equals its length.
+func Bound[T any](s []T, maxCap int) []T {
+	if cap(s) <= maxCap {
+		return s
+	}
+	return CloneN(s, len(s))
+}
+
+// FilterInPlace keeps the elements for which keep returns true, reusing the
+// array of s. The elements dropped past the new length are zeroed; hence, if
+// they are pointers (or contain some), what they reference can be garbage
+// collected.
+func FilterInPlace[T any](s []T, keep func(T) bool) []T {
+	n := 0
+	for _, v := range s {
+		if keep(v) {
+			s[n] = v
+			n++
+		}
+	}
+	var zero T
+	for i := n; i < len(s); i++ {
+		s[i] = zero
+	}
+	return s[:n]
+}
+
+// Chunk splits s into slices of size elements, the last one being possibly
+// shorter. The chunks share the array of s but are clipped, so appending to
+// one doesn't overwrite the next.
+func Chunk[T any](s []T, size int) [][]T {
+	if size <= 0 {
+		panic("sliceutil: chunk size should be positive")
+	}
+	chunks := make([][]T, 0, (len(s)+size-1)/size)
+	for i := 0; i < len(s); i += size {
+		end := i + size
+		if end > len(s) {
+			end = len(s)
+		}
+		chunks = append(chunks, s[i:end:end])
+	}
+	return chunks
+}
@@ -0,0 +1,133 @@
+package sliceutil
+
+import (
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// allocKB is printAlloc returning the value instead of printing it.
+func allocKB() uint64 {
+	runtime.GC()
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+	return m.Alloc / 1024
+}
+
+func receiveMessage() []byte {
+	return make([]byte, 1_000_000)
+}
+
+func TestCloneN_ReleasesBackingArray(t *testing.T) {
+	const n = 100
+
+	before := allocKB()
+	leaked := make([][]byte, n)
+	for i := range leaked {
+		leaked[i] = receiveMessage()[:5]
+	}
+	leakedKB := int64(allocKB()) - int64(before)
+	runtime.KeepAlive(leaked)
+	leaked = nil
+
+	before = allocKB()
+	types := make([][]byte, n)
+	for i := range types {
+		types[i] = CloneN(receiveMessage(), 5)
+	}
+	clonedKB := int64(allocKB()) - int64(before)
+	runtime.KeepAlive(types)
+
+	// 100 messages of 1 MB are retained by the sub-slices, not by the copies
+	if leakedKB < 90_000 || clonedKB > 1_000 {
+		t.Errorf("leaked: %d KB, cloned: %d KB", leakedKB, clonedKB)
+	}
+}
+
+type Foo struct {
+	v []byte
+}
+
+func TestFilterInPlace_ReleasesDroppedElements(t *testing.T) {
+	before := allocKB()
+	foos := make([]*Foo, 100)
+	for i := range foos {
+		foos[i] = &Foo{v: make([]byte, 1024*1024)}
+	}
+	i := 0
+	two := FilterInPlace(foos, func(*Foo) bool {
+		i++
+		return i <= 2
+	})
+	retainedKB := int64(allocKB()) - int64(before)
+	runtime.KeepAlive(two)
+
+	if len(two) != 2 || cap(two) != 100 {
+		t.Fatalf("len: %d, cap: %d", len(two), cap(two))
+	}
+	if foos[2] != nil {
+		t.Error("expected the dropped elements to be zeroed")
+	}
+	// The array of pointers is kept, not the 98 MB they referenced
+	if retainedKB > 5_000 {
+		t.Errorf("retained: %d KB", retainedKB)
+	}
+}
+
+func TestClipAndBound(t *testing.T) {
+	s := make([]int, 2, 10)
+	clipped := Clip(s)
+	clipped = append(clipped, 1)
+	if s[:3][2] != 0 {
+		t.Error("append to a clipped slice overwrote the original array")
+	}
+	_ = clipped
+
+	if b := Bound(s, 10); &b[0] != &s[0] {
+		t.Error("expected s to be returned as is")
+	}
+	if b := Bound(s, 5); &b[0] == &s[0] || cap(b) != 2 {
+		t.Error("expected a copy")
+	}
+}
+
+func TestChunk(t *testing.T) {
+	s := []int{1, 2, 3, 4, 5}
+	chunks := Chunk(s, 2)
+	expected := [][]int{{1, 2}, {3, 4}, {5}}
+	if !reflect.DeepEqual(chunks, expected) {
+		t.Fatalf("got: %v", chunks)
+	}
+	_ = append(chunks[0], 42)
+	if s[2] != 3 {
+		t.Error("appending to a chunk overwrote the next one")
+	}
+	if len(Chunk([]int{}, 3)) != 0 {
+		t.Error("expected no chunk")
+	}
+}
+
+func TestDebug(t *testing.T) {
+	var reports []Retention
+	EnableDebug(4, func(r Retention) {
+		reports = append(reports, r)
+	})
+	defer DisableDebug()
+
+	msg := receiveMessage()
+	_ = Sub(msg, 0, 5)
+	_ = Sub(msg, 0, len(msg)/2)
+	_ = Check(msg[10:20])
+	_ = Check(CloneN(msg, 5))
+
+	if len(reports) != 2 {
+		t.Fatalf("got: %v", reports)
+	}
+	if reports[0].PinnedBytes != 1_000_000 || reports[0].UsedBytes != 5 {
+		t.Errorf("got: %+v", reports[0])
+	}
+	if !strings.Contains(reports[0].Caller, "sliceutil_test.go") {
+		t.Errorf("caller: %s", reports[0].Caller)
+	}
+}
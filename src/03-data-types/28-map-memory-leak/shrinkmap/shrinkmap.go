@@ -0,0 +1,155 @@
+// Package shrinkmap provides a map reclaiming its memory after mass
+// deletions. A Go map never shrinks: its buckets stay allocated once
+// created, so the map is rebuilt instead.
+package shrinkmap
+
+import (
+	"sync"
+	"unsafe"
+)
+
+// Stats describes the size history of a Map.
+type Stats struct {
+	Len int
+	// Peak is the largest length since the last rebuild.
+	Peak int
+	// AllTimePeak is the largest length since the creation.
+	AllTimePeak int
+	Rebuilds    int
+	// BytesReclaimed estimates the memory released by the rebuilds from the
+	// size of the keys and values; the map overhead isn't counted.
+	BytesReclaimed uint64
+}
+
+// Map is a map safe for concurrent use, rebuilt when its length drops
+// below a ratio of its peak length.
+type Map[K comparable, V any] struct {
+	mu          sync.RWMutex
+	m           map[K]V
+	ratio       float64
+	minPeak     int
+	autoRebuild bool
+	stats       Stats
+}
+
+type options struct {
+	ratio       float64
+	minPeak     int
+	autoRebuild bool
+}
+
+type Option func(*options)
+
+// WithRatio sets the occupancy (length / peak) under which the map is
+// rebuilt. It defaults to 0.25.
+func WithRatio(ratio float64) Option {
+	return func(o *options) {
+		o.ratio = ratio
+	}
+}
+
+// WithMinPeak sets the peak under which the map is never rebuilt
+// automatically, as small maps aren't worth it. It defaults to 1024.
+func WithMinPeak(n int) Option {
+	return func(o *options) {
+		o.minPeak = n
+	}
+}
+
+// WithoutAutoRebuild leaves the rebuilds to explicit Rebuild calls.
+func WithoutAutoRebuild() Option {
+	return func(o *options) {
+		o.autoRebuild = false
+	}
+}
+
+func New[K comparable, V any](opts ...Option) *Map[K, V] {
+	o := options{ratio: 0.25, minPeak: 1024, autoRebuild: true}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return &Map[K, V]{
+		m:           make(map[K]V),
+		ratio:       o.ratio,
+		minPeak:     o.minPeak,
+		autoRebuild: o.autoRebuild,
+	}
+}
+
+func (m *Map[K, V]) Get(k K) (V, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	v, ok := m.m[k]
+	return v, ok
+}
+
+func (m *Map[K, V]) Set(k K, v V) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.m[k] = v
+	if n := len(m.m); n > m.stats.Peak {
+		m.stats.Peak = n
+		if n > m.stats.AllTimePeak {
+			m.stats.AllTimePeak = n
+		}
+	}
+}
+
+// Delete removes k and rebuilds the map if the occupancy became too low.
+func (m *Map[K, V]) Delete(k K) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.m, k)
+	if m.autoRebuild && m.stats.Peak >= m.minPeak &&
+		float64(len(m.m)) < m.ratio*float64(m.stats.Peak) {
+		m.rebuild()
+	}
+}
+
+func (m *Map[K, V]) Len() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.m)
+}
+
+// Range calls f for each entry until f returns false. The map is locked for
+// reading during the iteration, so f must not modify it.
+func (m *Map[K, V]) Range(f func(k K, v V) bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for k, v := range m.m {
+		if !f(k, v) {
+			return
+		}
+	}
+}
+
+// Rebuild copies the entries into a new map sized for the current length,
+// letting the old buckets be garbage collected.
+func (m *Map[K, V]) Rebuild() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.rebuild()
+}
+
+func (m *Map[K, V]) rebuild() {
+	fresh := make(map[K]V, len(m.m))
+	for k, v := range m.m {
+		fresh[k] = v
+	}
+	var k K
+	var v V
+	entry := uint64(unsafe.Sizeof(k) + unsafe.Sizeof(v))
+	m.stats.BytesReclaimed += uint64(m.stats.Peak-len(fresh)) * entry
+	m.stats.Rebuilds++
+	m.stats.Peak = len(fresh)
+	m.m = fresh
+}
+
+func (m *Map[K, V]) Stats() Stats {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	stats := m.stats
+	stats.Len = len(m.m)
+	return stats
+}
@@ -0,0 +1,149 @@
+package shrinkmap
+
+import (
+	"runtime"
+	"sync"
+	"testing"
+)
+
+func allocMB() uint64 {
+	runtime.GC()
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+	return m.Alloc / 1024 / 1024
+}
+
+func TestMap_AutoRebuild(t *testing.T) {
+	m := New[int, int](WithRatio(0.5), WithMinPeak(10))
+	for i := 0; i < 100; i++ {
+		m.Set(i, i)
+	}
+	for i := 0; i < 50; i++ {
+		m.Delete(i)
+	}
+	if stats := m.Stats(); stats.Rebuilds != 0 {
+		t.Fatalf("unexpected rebuild: %+v", stats)
+	}
+	m.Delete(50)
+
+	stats := m.Stats()
+	expected := Stats{
+		Len:            49,
+		Peak:           49,
+		AllTimePeak:    100,
+		Rebuilds:       1,
+		BytesReclaimed: 51 * 16,
+	}
+	if stats != expected {
+		t.Errorf("got: %+v, expected: %+v", stats, expected)
+	}
+	for i := 51; i < 100; i++ {
+		if v, ok := m.Get(i); !ok || v != i {
+			t.Fatalf("%d: got %d, %t", i, v, ok)
+		}
+	}
+}
+
+func TestMap_ManualRebuild(t *testing.T) {
+	m := New[int, int](WithoutAutoRebuild(), WithMinPeak(0))
+	for i := 0; i < 100; i++ {
+		m.Set(i, i)
+	}
+	for i := 0; i < 100; i++ {
+		m.Delete(i)
+	}
+	if m.Stats().Rebuilds != 0 {
+		t.Fatal("unexpected rebuild")
+	}
+	m.Rebuild()
+	if stats := m.Stats(); stats.Rebuilds != 1 || stats.Peak != 0 {
+		t.Errorf("got: %+v", stats)
+	}
+}
+
+func TestMap_ReclaimsMemory(t *testing.T) {
+	const n = 200_000
+	plain := make(map[int][128]byte)
+	shrinkable := New[int, [128]byte]()
+	for i := 0; i < n; i++ {
+		plain[i] = [128]byte{}
+	}
+	for i := 0; i < n; i++ {
+		delete(plain, i)
+	}
+	plainMB := allocMB()
+	runtime.KeepAlive(plain)
+	plain = nil
+
+	before := allocMB()
+	for i := 0; i < n; i++ {
+		shrinkable.Set(i, [128]byte{})
+	}
+	for i := 0; i < n; i++ {
+		shrinkable.Delete(i)
+	}
+	// Signed, as freeing plain may leave less allocated memory than before
+	shrinkableMB := int64(allocMB()) - int64(before)
+	runtime.KeepAlive(shrinkable)
+
+	if shrinkableMB*4 > int64(plainMB) {
+		t.Errorf("plain: %d MB, shrinkable: %d MB", plainMB, shrinkableMB)
+	}
+}
+
+func TestMap_Concurrent(t *testing.T) {
+	m := New[int, int](WithMinPeak(8))
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		g := g
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				k := g*1000 + i
+				m.Set(k, i)
+				m.Get(k)
+				m.Delete(k)
+			}
+		}()
+	}
+	wg.Wait()
+	if m.Len() != 0 {
+		t.Errorf("len: %d", m.Len())
+	}
+}
+
+const n = 10_000
+
+var global int
+
+func BenchmarkPlainMap(b *testing.B) {
+	var local int
+	for i := 0; i < b.N; i++ {
+		m := make(map[int]int)
+		for j := 0; j < n; j++ {
+			m[j] = j
+		}
+		for j := 0; j < n; j++ {
+			local += m[j]
+			delete(m, j)
+		}
+	}
+	global = local
+}
+
+func BenchmarkShrinkMap(b *testing.B) {
+	var local int
+	for i := 0; i < b.N; i++ {
+		m := New[int, int]()
+		for j := 0; j < n; j++ {
+			m.Set(j, j)
+		}
+		for j := 0; j < n; j++ {
+			v, _ := m.Get(j)
+			local += v
+			m.Delete(j)
+		}
+	}
+	global = local
+}
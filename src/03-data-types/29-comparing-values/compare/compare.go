@@ -0,0 +1,343 @@
+// Package compare compares values deeply like reflect.DeepEqual, but
+// reports the differences and can be configured with options.
+package compare
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+
+	"github.com/teivah/100-go-mistakes/src/03-data-types/19-floating-points/numerics"
+)
+
+// Difference is a value differing between the two compared values. Path
+// locates it from the root, e.g. "customer2.operations[1]". A and B are
+// formatted with %v, or "<missing>" when the value exists on one side only.
+type Difference struct {
+	Path string
+	A, B string
+}
+
+func (d Difference) String() string {
+	return fmt.Sprintf("%s: %s != %s", d.Path, d.A, d.B)
+}
+
+const missing = "<missing>"
+
+type options struct {
+	ignored     map[string]bool
+	equateEmpty bool
+	floatAbs    float64
+	floatRel    float64
+	approx      bool
+	comparers   map[reflect.Type]func(a, b reflect.Value) bool
+}
+
+type Option func(*options)
+
+// IgnoreFields skips struct fields identified by the name of their struct
+// type and their own name, e.g. "customer2.operations".
+func IgnoreFields(fields ...string) Option {
+	return func(o *options) {
+		for _, f := range fields {
+			o.ignored[f] = true
+		}
+	}
+}
+
+// EquateEmpty makes a nil slice or map equal to an empty one.
+func EquateEmpty() Option {
+	return func(o *options) {
+		o.equateEmpty = true
+	}
+}
+
+// EquateApprox compares floats with numerics.EqualEpsilon. NaN stays
+// different from everything, including NaN.
+func EquateApprox(absTol, relTol float64) Option {
+	return func(o *options) {
+		o.approx = true
+		o.floatAbs = absTol
+		o.floatRel = relTol
+	}
+}
+
+// Comparer compares the values of type T using f instead of walking them.
+// As reflect can't expose unexported fields, f isn't used for values
+// reached through unexported fields.
+func Comparer[T any](f func(a, b T) bool) Option {
+	return func(o *options) {
+		o.comparers[reflect.TypeOf((*T)(nil)).Elem()] = func(a, b reflect.Value) bool {
+			return f(a.Interface().(T), b.Interface().(T))
+		}
+	}
+}
+
+// Equal reports whether a and b are deeply equal.
+func Equal(a, b any, opts ...Option) bool {
+	return len(compare(a, b, true, opts)) == 0
+}
+
+// Diff returns the differences between a and b, or nil if they are equal.
+func Diff(a, b any, opts ...Option) []Difference {
+	return compare(a, b, false, opts)
+}
+
+func compare(a, b any, stopAtFirst bool, opts []Option) []Difference {
+	o := options{
+		ignored:   make(map[string]bool),
+		comparers: make(map[reflect.Type]func(a, b reflect.Value) bool),
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	w := walker{
+		options:     o,
+		stopAtFirst: stopAtFirst,
+		visited:     make(map[visit]bool),
+	}
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	root := ""
+	if va.IsValid() {
+		root = va.Type().Name()
+	}
+	w.walk(root, va, vb)
+	return w.diffs
+}
+
+// visit identifies a pair of references on the current path, to stop when a
+// cycle comes back to it.
+type visit struct {
+	a, b uintptr
+	typ  reflect.Type
+}
+
+type walker struct {
+	options
+	stopAtFirst bool
+	visited     map[visit]bool
+	diffs       []Difference
+}
+
+func (w *walker) done() bool {
+	return w.stopAtFirst && len(w.diffs) != 0
+}
+
+func (w *walker) report(path string, a, b reflect.Value) {
+	w.diffs = append(w.diffs, Difference{Path: path, A: format(a), B: format(b)})
+}
+
+func format(v reflect.Value) string {
+	if !v.IsValid() {
+		return missing
+	}
+	// fmt prints a reflect.Value as the value it holds, even unexported
+	return fmt.Sprintf("%v", v)
+}
+
+func (w *walker) walk(path string, a, b reflect.Value) {
+	if w.done() {
+		return
+	}
+	if !a.IsValid() || !b.IsValid() {
+		if a.IsValid() != b.IsValid() {
+			w.report(path, a, b)
+		}
+		return
+	}
+	if a.Type() != b.Type() {
+		w.diffs = append(w.diffs, Difference{
+			Path: path,
+			A:    a.Type().String(),
+			B:    b.Type().String(),
+		})
+		return
+	}
+	if f, ok := w.comparers[a.Type()]; ok && a.CanInterface() {
+		if !f(a, b) {
+			w.report(path, a, b)
+		}
+		return
+	}
+
+	switch a.Kind() {
+	case reflect.Bool:
+		if a.Bool() != b.Bool() {
+			w.report(path, a, b)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if a.Int() != b.Int() {
+			w.report(path, a, b)
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if a.Uint() != b.Uint() {
+			w.report(path, a, b)
+		}
+	case reflect.Float32, reflect.Float64:
+		if !w.equalFloat(a.Float(), b.Float()) {
+			w.report(path, a, b)
+		}
+	case reflect.Complex64, reflect.Complex128:
+		ca, cb := a.Complex(), b.Complex()
+		if !w.equalFloat(real(ca), real(cb)) || !w.equalFloat(imag(ca), imag(cb)) {
+			w.report(path, a, b)
+		}
+	case reflect.String:
+		if a.String() != b.String() {
+			w.report(path, a, b)
+		}
+	case reflect.Chan, reflect.UnsafePointer:
+		if a.Pointer() != b.Pointer() {
+			w.report(path, a, b)
+		}
+	case reflect.Func:
+		// Like reflect.DeepEqual, functions are only equal if both are nil
+		if !a.IsNil() || !b.IsNil() {
+			w.report(path, a, b)
+		}
+	case reflect.Array:
+		for i := 0; i < a.Len(); i++ {
+			w.walk(path+"["+strconv.Itoa(i)+"]", a.Index(i), b.Index(i))
+		}
+	case reflect.Slice:
+		w.walkSlice(path, a, b)
+	case reflect.Map:
+		w.walkMap(path, a, b)
+	case reflect.Pointer:
+		if a.IsNil() || b.IsNil() {
+			if a.IsNil() != b.IsNil() {
+				w.report(path, a, b)
+			}
+			return
+		}
+		if !w.enter(a, b) {
+			return
+		}
+		defer w.leave(a, b)
+		w.walk(path, a.Elem(), b.Elem())
+	case reflect.Interface:
+		if a.IsNil() || b.IsNil() {
+			if a.IsNil() != b.IsNil() {
+				w.report(path, a, b)
+			}
+			return
+		}
+		w.walk(path, a.Elem(), b.Elem())
+	case reflect.Struct:
+		t := a.Type()
+		for i := 0; i < t.NumField(); i++ {
+			name := t.Field(i).Name
+			if w.ignored[t.Name()+"."+name] {
+				continue
+			}
+			w.walk(path+"."+name, a.Field(i), b.Field(i))
+		}
+	}
+}
+
+func (w *walker) equalFloat(a, b float64) bool {
+	if w.approx {
+		return numerics.EqualEpsilon(a, b, w.floatAbs, w.floatRel)
+	}
+	return a == b
+}
+
+// enter records the pair of references on the current path. It returns false
+// if the pair is already on it, i.e. for a cycle, which is then considered
+// equal. Only the current path is tracked: a pair compared earlier elsewhere,
+// e.g. through slices sharing a backing array with another length, is
+// compared again.
+func (w *walker) enter(a, b reflect.Value) bool {
+	v := visit{a: a.Pointer(), b: b.Pointer(), typ: a.Type()}
+	if w.visited[v] {
+		return false
+	}
+	w.visited[v] = true
+	return true
+}
+
+func (w *walker) leave(a, b reflect.Value) {
+	delete(w.visited, visit{a: a.Pointer(), b: b.Pointer(), typ: a.Type()})
+}
+
+func (w *walker) nilOrEmpty(path string, a, b reflect.Value) (done bool) {
+	if a.IsNil() == b.IsNil() {
+		return false
+	}
+	if w.equateEmpty && a.Len() == 0 && b.Len() == 0 {
+		return true
+	}
+	w.report(path, a, b)
+	return true
+}
+
+func (w *walker) walkSlice(path string, a, b reflect.Value) {
+	if w.nilOrEmpty(path, a, b) {
+		return
+	}
+	if a.Len() != 0 && b.Len() != 0 {
+		if !w.enter(a, b) {
+			return
+		}
+		defer w.leave(a, b)
+	}
+	n := a.Len()
+	if b.Len() > n {
+		n = b.Len()
+	}
+	for i := 0; i < n && !w.done(); i++ {
+		var ea, eb reflect.Value
+		if i < a.Len() {
+			ea = a.Index(i)
+		}
+		if i < b.Len() {
+			eb = b.Index(i)
+		}
+		w.walk(path+"["+strconv.Itoa(i)+"]", ea, eb)
+	}
+}
+
+func (w *walker) walkMap(path string, a, b reflect.Value) {
+	if w.nilOrEmpty(path, a, b) {
+		return
+	}
+	if a.Len() != 0 && b.Len() != 0 {
+		if !w.enter(a, b) {
+			return
+		}
+		defer w.leave(a, b)
+	}
+
+	// The keys are looked up in the other map rather than matched by their
+	// representation, which isn't unique for interface keys. A NaN key is
+	// never found, so it's reported as missing from the other map.
+	type entry struct {
+		name   string
+		ea, eb reflect.Value
+	}
+	var entries []entry
+	iter := a.MapRange()
+	for iter.Next() {
+		k := iter.Key()
+		entries = append(entries, entry{name: fmt.Sprintf("%#v", k), ea: iter.Value(), eb: b.MapIndex(k)})
+	}
+	iter = b.MapRange()
+	for iter.Next() {
+		k := iter.Key()
+		if !a.MapIndex(k).IsValid() {
+			entries = append(entries, entry{name: fmt.Sprintf("%#v", k), eb: iter.Value()})
+		}
+	}
+	// Sorted for a deterministic report
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].name < entries[j].name
+	})
+
+	for _, e := range entries {
+		if w.done() {
+			return
+		}
+		w.walk(path+"["+e.name+"]", e.ea, e.eb)
+	}
+}
@@ -0,0 +1,174 @@
+package compare
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type customer2 struct {
+	id         string
+	operations []float64
+}
+
+type node struct {
+	Val  int
+	Next *node
+}
+
+func TestEqual_MatchesDeepEqual(t *testing.T) {
+	values := []any{
+		customer2{id: "x", operations: []float64{1.}},
+		customer2{id: "x", operations: []float64{2.}},
+		customer2{id: "x"},
+		map[string][]int{"a": {1}},
+		map[string][]int{"a": {2}},
+		[2]string{"a", "b"},
+		&node{Val: 1},
+		3,
+		"3",
+		nil,
+	}
+	for _, a := range values {
+		for _, b := range values {
+			if got, expected := Equal(a, b), reflect.DeepEqual(a, b); got != expected {
+				t.Errorf("%#v, %#v: got %t, expected %t", a, b, got, expected)
+			}
+		}
+	}
+}
+
+func TestDiff(t *testing.T) {
+	a := customer2{id: "x", operations: []float64{1, 2}}
+	b := customer2{id: "y", operations: []float64{1, 3, 4}}
+	got := Diff(a, b)
+	expected := []Difference{
+		{Path: "customer2.id", A: "x", B: "y"},
+		{Path: "customer2.operations[1]", A: "2", B: "3"},
+		{Path: "customer2.operations[2]", A: missing, B: "4"},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("got: %v, expected: %v", got, expected)
+	}
+
+	got = Diff(map[string]int{"a": 1, "b": 2}, map[string]int{"b": 3, "c": 4})
+	expected = []Difference{
+		{Path: `["a"]`, A: "1", B: missing},
+		{Path: `["b"]`, A: "2", B: "3"},
+		{Path: `["c"]`, A: missing, B: "4"},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("got: %v, expected: %v", got, expected)
+	}
+}
+
+func TestDiff_InterfaceKeys(t *testing.T) {
+	// int32(1) and int64(1) are both formatted as 1
+	a := map[any]int{int32(1): 1, int64(1): 2}
+	b := map[any]int{int32(1): 1, int64(1): 3}
+	if got := Diff(a, b); len(got) != 1 || got[0].A != "2" || got[0].B != "3" {
+		t.Errorf("got: %v", got)
+	}
+	if !Equal(a, map[any]int{int64(1): 2, int32(1): 1}) {
+		t.Error("expected equal maps")
+	}
+	if got := Diff(map[any]int{int32(1): 1}, map[any]int{int64(1): 1}); len(got) != 2 {
+		t.Errorf("expected both keys to be missing from the other map, got: %v", got)
+	}
+}
+
+func TestOptions(t *testing.T) {
+	a := customer2{id: "x", operations: nil}
+	b := customer2{id: "x", operations: []float64{}}
+	if Equal(a, b) {
+		t.Error("nil and empty should differ by default")
+	}
+	if !Equal(a, b, EquateEmpty()) {
+		t.Error("nil and empty should be equal with EquateEmpty")
+	}
+
+	c := customer2{id: "y", operations: []float64{1}}
+	if !Equal(a, c, IgnoreFields("customer2.id", "customer2.operations")) {
+		t.Error("expected the fields to be ignored")
+	}
+
+	x := 0.1
+	d := customer2{operations: []float64{x + 0.2}}
+	e := customer2{operations: []float64{0.3}}
+	if Equal(d, e) {
+		t.Error("0.1+0.2 isn't exactly 0.3")
+	}
+	if !Equal(d, e, EquateApprox(0, 1e-9)) {
+		t.Error("0.1+0.2 should be approximately 0.3")
+	}
+	nan := customer2{operations: []float64{math.NaN()}}
+	if Equal(nan, nan, EquateApprox(1, 1)) {
+		t.Error("NaN is never equal")
+	}
+}
+
+func TestComparer(t *testing.T) {
+	type event struct {
+		Name string
+		At   time.Time
+	}
+	now := time.Now()
+	a := event{Name: "created", At: now}
+	b := event{Name: "created", At: now.UTC()}
+	if Equal(a, b) {
+		t.Error("the locations differ")
+	}
+	if !Equal(a, b, Comparer(func(a, b time.Time) bool { return a.Equal(b) })) {
+		t.Error("expected the comparer to be used")
+	}
+
+	caseInsensitive := Comparer(strings.EqualFold)
+	if !Equal([]string{"Foo"}, []string{"foo"}, caseInsensitive) {
+		t.Error("expected the comparer to be used on elements")
+	}
+}
+
+func TestCycles(t *testing.T) {
+	a := &node{Val: 1}
+	a.Next = &node{Val: 2, Next: a}
+	b := &node{Val: 1}
+	b.Next = &node{Val: 2, Next: b}
+	if !Equal(a, b) {
+		t.Error("expected equal cycles")
+	}
+
+	c := &node{Val: 1}
+	c.Next = &node{Val: 3, Next: c}
+	diffs := Diff(a, c)
+	if len(diffs) != 1 || diffs[0].Path != ".Next.Val" {
+		t.Errorf("got: %v", diffs)
+	}
+
+	m1 := map[string]any{}
+	m1["self"] = m1
+	m2 := map[string]any{}
+	m2["self"] = m2
+	if !Equal(m1, m2) {
+		t.Error("expected equal cyclic maps")
+	}
+}
+
+func TestSharedBackingArrays(t *testing.T) {
+	type pair struct {
+		A, B []int
+	}
+	s := []int{1, 2, 3}
+	u := []int{1, 2, 4}
+	a := pair{A: s[:2], B: s[:3]}
+	b := pair{A: u[:2], B: u[:3]}
+
+	if Equal(a, b) != reflect.DeepEqual(a, b) {
+		t.Errorf("Equal: %t, DeepEqual: %t", Equal(a, b), reflect.DeepEqual(a, b))
+	}
+	diffs := Diff(a, b)
+	if len(diffs) != 1 || diffs[0].Path != "pair.B[2]" {
+		t.Errorf("got: %v", diffs)
+	}
+}
@@ -3,6 +3,8 @@ package main
 import (
 	"fmt"
 	"reflect"
+
+	"github.com/teivah/100-go-mistakes/src/03-data-types/29-comparing-values/compare"
 )
 
 type customer1 struct {
@@ -37,6 +39,7 @@ func main() {
 	cust41 := customer2{id: "x", operations: []float64{1.}}
 	cust42 := customer2{id: "x", operations: []float64{1.}}
 	fmt.Println(reflect.DeepEqual(cust41, cust42))
+	fmt.Println(compare.Diff(cust41, customer2{id: "y", operations: []float64{}}, compare.EquateEmpty()))
 }
 
 func (a customer2) equal(b customer2) bool {
@@ -0,0 +1,311 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/teivah/100-go-mistakes/src/03-data-types/18-integer-overflows/checked"
+)
+
+var (
+	ErrUnknownAccount = errors.New("unknown account")
+	ErrInvalidAmount  = errors.New("amount should be strictly positive")
+)
+
+// OverdraftError is returned when a batch would make a balance negative.
+type OverdraftError[K comparable] struct {
+	Account K
+	Balance int64
+}
+
+func (e *OverdraftError[K]) Error() string {
+	return fmt.Sprintf("overdraft on account %v: balance would be %d", e.Account, e.Balance)
+}
+
+// Book gives access to the balances of accounts identified by K. The
+// balances are integers in minor units (cents), so that no rounding error
+// accumulates.
+type Book[K comparable] interface {
+	Balance(k K) (int64, bool)
+	SetBalance(k K, balance int64)
+}
+
+// SliceBook updates a slice of accounts in place, identified by their index.
+type SliceBook []account
+
+func (b SliceBook) Balance(i int) (int64, bool) {
+	if i < 0 || i >= len(b) {
+		return 0, false
+	}
+	return b[i].balance, true
+}
+
+func (b SliceBook) SetBalance(i int, balance int64) {
+	// Not `for _, a := range b`: a would be a copy
+	b[i].balance = balance
+}
+
+// MapBook updates a map of accounts; as map values aren't addressable, the
+// accounts are replaced.
+type MapBook[K comparable] map[K]account
+
+func (b MapBook[K]) Balance(k K) (int64, bool) {
+	a, ok := b[k]
+	return a.balance, ok
+}
+
+func (b MapBook[K]) SetBalance(k K, balance int64) {
+	a := b[k]
+	a.balance = balance
+	b[k] = a
+}
+
+// Op is a credit or a debit of a strictly positive amount in cents.
+type Op[K comparable] struct {
+	Account K
+	Amount  int64
+	Debit   bool
+}
+
+func Credit[K comparable](k K, amount int64) Op[K] {
+	return Op[K]{Account: k, Amount: amount}
+}
+
+func Debit[K comparable](k K, amount int64) Op[K] {
+	return Op[K]{Account: k, Amount: amount, Debit: true}
+}
+
+// delta returns the signed change of the balance.
+func (op Op[K]) delta() int64 {
+	if op.Debit {
+		return -op.Amount
+	}
+	return op.Amount
+}
+
+// Entry is a change recorded in a journal.
+type Entry[K comparable] struct {
+	Seq   uint64 `json:"seq"`
+	Batch uint64 `json:"batch"`
+	// BatchSize is the number of entries of the batch, used by Replay to
+	// detect an incomplete batch.
+	BatchSize int `json:"batch_size"`
+	Account   K   `json:"account"`
+	// Amount is the signed change in cents: negative for a debit.
+	Amount int64     `json:"amount"`
+	Time   time.Time `json:"time"`
+}
+
+// Journal stores the entries; it's append-only.
+type Journal[K comparable] interface {
+	// Append stores all the entries or none of them.
+	Append(entries []Entry[K]) error
+}
+
+type MemoryJournal[K comparable] struct {
+	entries []Entry[K]
+}
+
+func (j *MemoryJournal[K]) Append(entries []Entry[K]) error {
+	j.entries = append(j.entries, entries...)
+	return nil
+}
+
+// Entries returns a copy of the entries.
+func (j *MemoryJournal[K]) Entries() []Entry[K] {
+	return append([]Entry[K](nil), j.entries...)
+}
+
+// JSONJournal writes a JSON line per entry, read back with ReadJournal. A
+// batch is written at once. If the write fails and w has a Truncate method,
+// like an *os.File opened with os.O_APPEND, the partial batch is truncated;
+// otherwise, the journal refuses the next batches, which would be glued to
+// the partial line. Only a crash can then leave a partial last batch, which
+// Replay skips.
+type JSONJournal[K comparable] struct {
+	w      io.Writer
+	size   int64
+	broken error
+}
+
+func NewJSONJournal[K comparable](w io.Writer) *JSONJournal[K] {
+	j := &JSONJournal[K]{w: w}
+	if f, ok := w.(*os.File); ok {
+		if info, err := f.Stat(); err == nil {
+			j.size = info.Size()
+		}
+	}
+	return j
+}
+
+func (j *JSONJournal[K]) Append(entries []Entry[K]) error {
+	if j.broken != nil {
+		return j.broken
+	}
+	var buf []byte
+	for _, e := range entries {
+		b, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		buf = append(append(buf, b...), '\n')
+	}
+	n, err := j.w.Write(buf)
+	if err == nil {
+		j.size += int64(n)
+		return nil
+	}
+	if n > 0 {
+		t, ok := j.w.(interface{ Truncate(size int64) error })
+		if !ok || t.Truncate(j.size) != nil {
+			j.broken = fmt.Errorf("journal left with a partial batch: %w", err)
+		}
+	}
+	return err
+}
+
+// ReadJournal reads the entries written by a JSONJournal. A last line
+// without a newline, left by an interrupted write, is ignored.
+func ReadJournal[K comparable](r io.Reader) ([]Entry[K], error) {
+	var entries []Entry[K]
+	br := bufio.NewReader(r)
+	for {
+		line, err := br.ReadBytes('\n')
+		if err == io.EOF {
+			return entries, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		var e Entry[K]
+		if err := json.Unmarshal(line, &e); err != nil {
+			return nil, fmt.Errorf("entry %d: %w", len(entries)+1, err)
+		}
+		entries = append(entries, e)
+	}
+}
+
+// Ledger applies batches of operations to a book and records them in a
+// journal. It's safe for concurrent use as long as the book is only updated
+// through the ledger.
+type Ledger[K comparable] struct {
+	mu      sync.Mutex
+	book    Book[K]
+	journal Journal[K]
+	seq     uint64
+	batch   uint64
+	now     func() time.Time
+}
+
+func NewLedger[K comparable](book Book[K], journal Journal[K]) *Ledger[K] {
+	return &Ledger[K]{book: book, journal: journal, now: time.Now}
+}
+
+// ResumeLedger returns a ledger appending to a journal holding entries, so
+// that the sequence and batch numbers carry on instead of restarting at 1.
+// The book should hold the balances rebuilt by Replay.
+func ResumeLedger[K comparable](book Book[K], journal Journal[K], entries []Entry[K]) *Ledger[K] {
+	l := NewLedger(book, journal)
+	for _, e := range entries {
+		if e.Seq > l.seq {
+			l.seq = e.Seq
+		}
+		if e.Batch > l.batch {
+			l.batch = e.Batch
+		}
+	}
+	return l
+}
+
+// Apply applies a batch atomically: if an operation is invalid or a balance
+// would become negative at any point, nothing is applied nor journaled. The
+// numbers of a batch whose journaling failed aren't reused, as part of it
+// may have been written.
+func (l *Ledger[K]) Apply(ops ...Op[K]) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	balances := make(map[K]int64, len(ops))
+	for _, op := range ops {
+		if op.Amount <= 0 {
+			return fmt.Errorf("account %v: %w", op.Account, ErrInvalidAmount)
+		}
+		balance, ok := balances[op.Account]
+		if !ok {
+			if balance, ok = l.book.Balance(op.Account); !ok {
+				return fmt.Errorf("%w: %v", ErrUnknownAccount, op.Account)
+			}
+		}
+		balance, err := checked.Add(balance, op.delta())
+		if err != nil {
+			return fmt.Errorf("account %v: %w", op.Account, err)
+		}
+		if balance < 0 {
+			return &OverdraftError[K]{Account: op.Account, Balance: balance}
+		}
+		balances[op.Account] = balance
+	}
+
+	now := l.now()
+	entries := make([]Entry[K], len(ops))
+	for i, op := range ops {
+		entries[i] = Entry[K]{
+			Seq:       l.seq + uint64(i) + 1,
+			Batch:     l.batch + 1,
+			BatchSize: len(ops),
+			Account:   op.Account,
+			Amount:    op.delta(),
+			Time:      now,
+		}
+	}
+	l.seq += uint64(len(ops))
+	l.batch++
+	if err := l.journal.Append(entries); err != nil {
+		return fmt.Errorf("journaling batch: %w", err)
+	}
+
+	for k, balance := range balances {
+		l.book.SetBalance(k, balance)
+	}
+	return nil
+}
+
+// Replay applies the journaled amounts to a book, typically holding the
+// opening balances, to rebuild the balances. The entries must be in journal
+// order. An incomplete last batch, e.g. after a failed write, is skipped;
+// an incomplete batch elsewhere means a corrupted journal.
+func Replay[K comparable](entries []Entry[K], book Book[K]) error {
+	for len(entries) > 0 {
+		n := 1
+		for n < len(entries) && entries[n].Batch == entries[0].Batch {
+			n++
+		}
+		batch := entries[:n]
+		entries = entries[n:]
+		if n != batch[0].BatchSize {
+			if len(entries) == 0 {
+				return nil
+			}
+			return fmt.Errorf("batch %d: %d entries instead of %d", batch[0].Batch, n, batch[0].BatchSize)
+		}
+
+		for _, e := range batch {
+			balance, ok := book.Balance(e.Account)
+			if !ok {
+				return fmt.Errorf("entry %d: %w: %v", e.Seq, ErrUnknownAccount, e.Account)
+			}
+			balance, err := checked.Add(balance, e.Amount)
+			if err != nil {
+				return fmt.Errorf("entry %d: %w", e.Seq, err)
+			}
+			book.SetBalance(e.Account, balance)
+		}
+	}
+	return nil
+}
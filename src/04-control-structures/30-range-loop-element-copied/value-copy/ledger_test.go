@@ -0,0 +1,235 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/teivah/100-go-mistakes/src/03-data-types/18-integer-overflows/checked"
+)
+
+func testAccounts() []account {
+	return []account{
+		{balance: 100},
+		{balance: 200},
+		{balance: 300},
+	}
+}
+
+func TestLedger_SliceInPlace(t *testing.T) {
+	accounts := testAccounts()
+	journal := &MemoryJournal[int]{}
+	ledger := NewLedger[int](SliceBook(accounts), journal)
+
+	if err := ledger.Apply(Credit(0, 1000), Debit(1, 50), Credit(1, 10)); err != nil {
+		t.Fatal(err)
+	}
+	expected := []account{{balance: 1100}, {balance: 160}, {balance: 300}}
+	if !reflect.DeepEqual(accounts, expected) {
+		t.Errorf("got: %v, expected: %v", accounts, expected)
+	}
+	if n := len(journal.Entries()); n != 3 {
+		t.Errorf("journal: %d entries", n)
+	}
+}
+
+func TestLedger_Map(t *testing.T) {
+	accounts := MapBook[string]{"alice": {balance: 10}}
+	ledger := NewLedger[string](accounts, &MemoryJournal[string]{})
+	if err := ledger.Apply(Debit("alice", 10)); err != nil {
+		t.Fatal(err)
+	}
+	if accounts["alice"].balance != 0 {
+		t.Errorf("got: %v", accounts["alice"])
+	}
+	if err := ledger.Apply(Credit("bob", 1)); !errors.Is(err, ErrUnknownAccount) {
+		t.Errorf("expected ErrUnknownAccount, got: %v", err)
+	}
+}
+
+func TestLedger_AtomicBatch(t *testing.T) {
+	accounts := testAccounts()
+	journal := &MemoryJournal[int]{}
+	ledger := NewLedger[int](SliceBook(accounts), journal)
+
+	// The credit is applied before the overdraft, which still rejects it
+	err := ledger.Apply(Credit(0, 1), Debit(1, 150), Debit(1, 100))
+	var overdraft *OverdraftError[int]
+	if !errors.As(err, &overdraft) || overdraft.Account != 1 || overdraft.Balance != -50 {
+		t.Fatalf("expected an overdraft error, got: %v", err)
+	}
+	for _, op := range []Op[int]{Credit(0, 0), Credit(0, -5), Debit(0, -5)} {
+		if err := ledger.Apply(op); !errors.Is(err, ErrInvalidAmount) {
+			t.Errorf("%+v: expected ErrInvalidAmount, got: %v", op, err)
+		}
+	}
+	if err := ledger.Apply(Credit(0, math.MaxInt64)); !errors.Is(err, checked.ErrOverflow) {
+		t.Errorf("expected ErrOverflow, got: %v", err)
+	}
+
+	if !reflect.DeepEqual(accounts, testAccounts()) {
+		t.Errorf("balances changed: %v", accounts)
+	}
+	if n := len(journal.Entries()); n != 0 {
+		t.Errorf("journal: %d entries", n)
+	}
+}
+
+type failingJournal struct{}
+
+func (failingJournal) Append([]Entry[int]) error {
+	return errors.New("disk full")
+}
+
+func TestLedger_JournalFailure(t *testing.T) {
+	accounts := testAccounts()
+	ledger := NewLedger[int](SliceBook(accounts), failingJournal{})
+	if err := ledger.Apply(Credit(0, 1)); err == nil {
+		t.Fatal("expected an error")
+	}
+	if !reflect.DeepEqual(accounts, testAccounts()) {
+		t.Errorf("balances changed: %v", accounts)
+	}
+}
+
+func TestReplay(t *testing.T) {
+	accounts := testAccounts()
+	var buf bytes.Buffer
+	ledger := NewLedger[int](SliceBook(accounts), NewJSONJournal[int](&buf))
+	ledger.now = func() time.Time { return time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC) }
+	_ = ledger.Apply(Credit(0, 1000))
+	_ = ledger.Apply(Debit(2, 300), Credit(1, 50))
+	_ = ledger.Apply(Credit(2, 1))
+
+	entries, err := ReadJournal[int](bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedEntry := Entry[int]{Seq: 3, Batch: 2, BatchSize: 2, Account: 1, Amount: 50, Time: ledger.now()}
+	if entries[2] != expectedEntry {
+		t.Errorf("got: %+v", entries[2])
+	}
+
+	rebuilt := testAccounts()
+	if err := Replay[int](entries, SliceBook(rebuilt)); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(rebuilt, accounts) {
+		t.Errorf("got: %v, expected: %v", rebuilt, accounts)
+	}
+
+	// An interrupted write leaves an incomplete last batch, skipped
+	partial := buf.Bytes()[:bytes.LastIndexByte(buf.Bytes()[:buf.Len()-1], '\n')+1]
+	partial = append(partial, `{"seq":4,"batch":3,"batch_size":2,"account":0,"amount":1}`+"\n"+`{"seq":5`...)
+	entries, err = ReadJournal[int](bytes.NewReader(partial))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rebuilt = testAccounts()
+	if err := Replay[int](entries, SliceBook(rebuilt)); err != nil {
+		t.Fatal(err)
+	}
+	expected := []account{{balance: 1100}, {balance: 250}, {balance: 0}}
+	if !reflect.DeepEqual(rebuilt, expected) {
+		t.Errorf("got: %v, expected: %v", rebuilt, expected)
+	}
+
+	// Elsewhere, it's a corrupted journal
+	corrupted := append(entries[:1:1], entries[2:]...)
+	if err := Replay[int](corrupted, SliceBook(testAccounts())); err == nil {
+		t.Error("expected an error")
+	}
+}
+
+// flakyFile fails the write number failAt after writing half of it.
+type flakyFile struct {
+	bytes.Buffer
+	writes, failAt int
+}
+
+func (f *flakyFile) Write(p []byte) (int, error) {
+	f.writes++
+	if f.writes == f.failAt {
+		n, _ := f.Buffer.Write(p[:len(p)/2])
+		return n, errors.New("disk full")
+	}
+	return f.Buffer.Write(p)
+}
+
+func (f *flakyFile) Truncate(size int64) error {
+	f.Buffer.Truncate(int(size))
+	return nil
+}
+
+func TestLedger_FailedAppend(t *testing.T) {
+	accounts := testAccounts()
+	file := &flakyFile{failAt: 2}
+	ledger := NewLedger[int](SliceBook(accounts), NewJSONJournal[int](file))
+	if err := ledger.Apply(Credit(0, 1)); err != nil {
+		t.Fatal(err)
+	}
+	if err := ledger.Apply(Credit(1, 1), Credit(2, 1)); err == nil {
+		t.Fatal("expected an error")
+	}
+	if err := ledger.Apply(Debit(0, 1)); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := ReadJournal[int](bytes.NewReader(file.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[1].Seq != 4 || entries[1].Batch != 3 {
+		t.Fatalf("got: %+v", entries)
+	}
+	rebuilt := testAccounts()
+	if err := Replay[int](entries, SliceBook(rebuilt)); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(rebuilt, accounts) {
+		t.Errorf("got: %v, expected: %v", rebuilt, accounts)
+	}
+
+	// After a restart, the numbers carry on
+	resumed := ResumeLedger[int](SliceBook(rebuilt), NewJSONJournal[int](file), entries)
+	if err := resumed.Apply(Credit(0, 1)); err != nil {
+		t.Fatal(err)
+	}
+	entries, _ = ReadJournal[int](bytes.NewReader(file.Bytes()))
+	if last := entries[len(entries)-1]; last.Seq != 5 || last.Batch != 4 {
+		t.Errorf("got: %+v", last)
+	}
+}
+
+func TestJSONJournal_NotTruncatable(t *testing.T) {
+	journal := NewJSONJournal[int](struct{ io.Writer }{&flakyFile{failAt: 1}})
+	if err := journal.Append([]Entry[int]{{Seq: 1}}); err == nil {
+		t.Fatal("expected an error")
+	}
+	// The next entries would follow the partial line
+	if err := journal.Append([]Entry[int]{{Seq: 2}}); err == nil {
+		t.Error("expected the journal to be broken")
+	}
+}
+
+func TestLedger_Concurrent(t *testing.T) {
+	accounts := testAccounts()
+	ledger := NewLedger[int](SliceBook(accounts), &MemoryJournal[int]{})
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = ledger.Apply(Debit(0, 1), Credit(1, 1))
+		}()
+	}
+	wg.Wait()
+	if accounts[0].balance != 0 || accounts[1].balance != 300 {
+		t.Errorf("got: %v", accounts)
+	}
+}
@@ -6,48 +6,49 @@ import (
 )
 
 type account struct {
-	balance float32
+	// balance is in cents
+	balance int64
 }
 
 func main() {
 	accounts := createAccounts()
 	for _, a := range accounts {
-		a.balance += 1000
+		a.balance += 1000_00
 	}
 	fmt.Println(accounts)
 
 	accounts = createAccounts()
 	for i := range accounts {
-		accounts[i].balance += 1000
+		accounts[i].balance += 1000_00
 	}
 	fmt.Println(accounts)
 
 	accounts = createAccounts()
 	for i := 0; i < len(accounts); i++ {
-		accounts[i].balance += 1000
+		accounts[i].balance += 1000_00
 	}
 	fmt.Println(accounts)
 
 	accountsPtr := createAccountsPtr()
 	for _, a := range accountsPtr {
-		a.balance += 1000
+		a.balance += 1000_00
 	}
 	printAccountsPtr(accountsPtr)
 }
 
 func createAccounts() []account {
 	return []account{
-		{balance: 100.},
-		{balance: 200.},
-		{balance: 300.},
+		{balance: 100_00},
+		{balance: 200_00},
+		{balance: 300_00},
 	}
 }
 
 func createAccountsPtr() []*account {
 	return []*account{
-		{balance: 100.},
-		{balance: 200.},
-		{balance: 300.},
+		{balance: 100_00},
+		{balance: 200_00},
+		{balance: 300_00},
 	}
 }
 
@@ -56,7 +57,7 @@ func printAccountsPtr(accounts []*account) {
 	sb.WriteString("[")
 	s := make([]string, len(accounts))
 	for i, account := range accounts {
-		s[i] = fmt.Sprintf("{%.0f}", account.balance)
+		s[i] = fmt.Sprintf("{%d}", account.balance)
 	}
 	sb.WriteString(strings.Join(s, " "))
 	sb.WriteString("]")
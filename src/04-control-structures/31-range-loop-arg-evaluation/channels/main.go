@@ -1,6 +1,10 @@
 package main
 
-import "fmt"
+import (
+	"fmt"
+
+	"github.com/teivah/100-go-mistakes/src/04-control-structures/31-range-loop-arg-evaluation/channels/mux"
+)
 
 func main() {
 	ch1 := make(chan int, 3)
@@ -24,4 +28,21 @@ func main() {
 		fmt.Println(v)
 		ch = ch2
 	}
+
+	// With a mux, the source can be switched while ranging over the output
+	ch3 := make(chan int, 1)
+	ch3 <- 0
+	ch4 := make(chan int, 3)
+	ch4 <- 10
+	ch4 <- 11
+	ch4 <- 12
+	close(ch4)
+
+	m := mux.New[int](mux.Ordered, 0)
+	id, _ := m.Attach(ch3)
+	m.Seal()
+	for v := range m.Out() {
+		fmt.Println(v)
+		m.Swap(id, ch4)
+	}
 }
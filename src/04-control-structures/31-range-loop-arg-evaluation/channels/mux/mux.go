@@ -0,0 +1,216 @@
+// Package mux merges channels into a single output channel, with sources
+// attached, detached and swapped at runtime.
+//
+// Reassigning a channel in a range loop doesn't switch sources, as the range
+// expression is evaluated once. With a Mux, the consumer ranges over Out while
+// the sources change.
+package mux
+
+import (
+	"errors"
+	"reflect"
+)
+
+var (
+	// ErrClosed is returned when changing the sources of a sealed Mux.
+	ErrClosed = errors.New("mux sealed")
+	// ErrNilSource is returned when attaching a nil channel in Ordered mode,
+	// where it would block the next sources forever.
+	ErrNilSource = errors.New("nil source in ordered mode")
+)
+
+// Mode defines the order in which the sources are read.
+type Mode int
+
+const (
+	// Fair reads from any ready source, picked uniformly at random like a
+	// select statement, so that a busy source can't starve the others.
+	Fair Mode = iota
+	// Ordered reads the sources one after the other in attach order: the
+	// next source is only read once the previous one is closed or detached.
+	Ordered
+)
+
+// ID identifies an attached source.
+type ID uint64
+
+type source[T any] struct {
+	id ID
+	ch reflect.Value
+}
+
+// Mux forwards the values of its sources to its output. Its methods are
+// safe for concurrent use.
+type Mux[T any] struct {
+	mode Mode
+	out  chan T
+	ctl  chan func()
+	done chan struct{}
+
+	// Owned by the run goroutine
+	sources []source[T]
+	nextID  ID
+	sealed  bool
+}
+
+// New starts a Mux whose output has the given buffer size. Its goroutine
+// runs until the Mux is sealed (see Seal and Stop) and drained: a Mux never
+// sealed leaks it.
+func New[T any](mode Mode, buffer int) *Mux[T] {
+	m := &Mux[T]{
+		mode: mode,
+		out:  make(chan T, buffer),
+		ctl:  make(chan func()),
+		done: make(chan struct{}),
+	}
+	go m.run()
+	return m
+}
+
+// Merge returns the output of a sealed Mux reading the given channels.
+func Merge[T any](mode Mode, buffer int, chs ...<-chan T) <-chan T {
+	m := New[T](mode, buffer)
+	for _, ch := range chs {
+		_, _ = m.Attach(ch)
+	}
+	m.Seal()
+	return m.Out()
+}
+
+// Out returns the output channel, closed once the Mux is sealed and all its
+// sources are closed or detached.
+func (m *Mux[T]) Out() <-chan T {
+	return m.out
+}
+
+// do runs f in the run goroutine and waits for it.
+func (m *Mux[T]) do(f func()) bool {
+	done := make(chan struct{})
+	select {
+	case m.ctl <- func() {
+		f()
+		close(done)
+	}:
+		<-done
+		return true
+	case <-m.done:
+		return false
+	}
+}
+
+// Attach adds a source. In Fair mode, a nil channel is accepted and never
+// read, like in a select statement; it is rejected in Ordered mode.
+func (m *Mux[T]) Attach(ch <-chan T) (ID, error) {
+	if ch == nil && m.mode == Ordered {
+		return 0, ErrNilSource
+	}
+	var id ID
+	err := ErrClosed
+	m.do(func() {
+		if m.sealed {
+			return
+		}
+		m.nextID++
+		id = m.nextID
+		m.sources = append(m.sources, source[T]{id: id, ch: reflect.ValueOf(ch)})
+		err = nil
+	})
+	return id, err
+}
+
+// Detach removes a source and reports whether it was attached. Once Detach
+// returns, no value is read from the source anymore; a value already read
+// is still forwarded.
+func (m *Mux[T]) Detach(id ID) bool {
+	found := false
+	m.do(func() {
+		for i, s := range m.sources {
+			if s.id == id {
+				m.sources = append(m.sources[:i], m.sources[i+1:]...)
+				found = true
+				return
+			}
+		}
+	})
+	return found
+}
+
+// Swap replaces the channel of a source, keeping its position in Ordered
+// mode, and reports whether the source was attached. Like Attach, it
+// rejects a nil channel in Ordered mode.
+func (m *Mux[T]) Swap(id ID, ch <-chan T) bool {
+	if ch == nil && m.mode == Ordered {
+		return false
+	}
+	found := false
+	m.do(func() {
+		for i, s := range m.sources {
+			if s.id == id {
+				m.sources[i].ch = reflect.ValueOf(ch)
+				found = true
+				return
+			}
+		}
+	})
+	return found
+}
+
+// Seal prevents attaching sources, so that the output is closed once the
+// attached sources are drained. Detaching and swapping are still possible.
+func (m *Mux[T]) Seal() {
+	m.do(func() {
+		m.sealed = true
+	})
+}
+
+// Stop detaches all the sources and seals the Mux. The output is closed once
+// a value already read, if any, is consumed.
+func (m *Mux[T]) Stop() {
+	m.do(func() {
+		m.sources = nil
+		m.sealed = true
+	})
+}
+
+func (m *Mux[T]) run() {
+	defer close(m.done)
+	defer close(m.out)
+
+	ctlCase := reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(m.ctl)}
+	var pending reflect.Value
+	var cases []reflect.SelectCase
+	for {
+		if !pending.IsValid() && m.sealed && len(m.sources) == 0 {
+			return
+		}
+
+		cases = append(cases[:0], ctlCase)
+		switch {
+		case pending.IsValid():
+			cases = append(cases, reflect.SelectCase{
+				Dir:  reflect.SelectSend,
+				Chan: reflect.ValueOf(m.out),
+				Send: pending,
+			})
+		case m.mode == Ordered && len(m.sources) > 0:
+			cases = append(cases, reflect.SelectCase{Dir: reflect.SelectRecv, Chan: m.sources[0].ch})
+		default:
+			for _, s := range m.sources {
+				cases = append(cases, reflect.SelectCase{Dir: reflect.SelectRecv, Chan: s.ch})
+			}
+		}
+
+		chosen, v, ok := reflect.Select(cases)
+		switch {
+		case chosen == 0:
+			v.Interface().(func())()
+		case pending.IsValid():
+			pending = reflect.Value{}
+		case !ok:
+			// Closed source; the index matches as the sources didn't change
+			m.sources = append(m.sources[:chosen-1], m.sources[chosen:]...)
+		default:
+			pending = v
+		}
+	}
+}
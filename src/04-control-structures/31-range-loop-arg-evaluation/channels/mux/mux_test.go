@@ -0,0 +1,177 @@
+package mux
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func produce(values ...int) <-chan int {
+	ch := make(chan int, len(values))
+	for _, v := range values {
+		ch <- v
+	}
+	close(ch)
+	return ch
+}
+
+func collect(t *testing.T, ch <-chan int) []int {
+	t.Helper()
+	var got []int
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case v, ok := <-ch:
+			if !ok {
+				return got
+			}
+			got = append(got, v)
+		case <-timeout:
+			t.Fatalf("output not closed, got: %v", got)
+		}
+	}
+}
+
+func TestMerge_Ordered(t *testing.T) {
+	got := collect(t, Merge(Ordered, 0, produce(0, 1, 2), produce(10, 11, 12)))
+	if expected := []int{0, 1, 2, 10, 11, 12}; !reflect.DeepEqual(got, expected) {
+		t.Errorf("got: %v, expected: %v", got, expected)
+	}
+}
+
+func TestMerge_Fair(t *testing.T) {
+	busy := make(chan int)
+	go func() {
+		for i := 0; i < 1000; i++ {
+			busy <- 0
+		}
+		close(busy)
+	}()
+	got := collect(t, Merge(Fair, 0, busy, produce(1, 1, 1)))
+
+	if len(got) != 1003 {
+		t.Fatalf("got %d values", len(got))
+	}
+	// The slow source must not wait for the busy one to be drained
+	last := 0
+	for i, v := range got {
+		if v == 1 {
+			last = i
+		}
+	}
+	if last == len(got)-1 {
+		t.Errorf("source starved")
+	}
+}
+
+func TestMerge_NilAndNoSources(t *testing.T) {
+	if got := collect(t, Merge[int](Fair, 0)); len(got) != 0 {
+		t.Errorf("got: %v", got)
+	}
+	m := New[int](Fair, 0)
+	id, _ := m.Attach(nil)
+	m.Seal()
+	select {
+	case <-m.Out():
+		t.Fatal("nil source read")
+	case <-time.After(10 * time.Millisecond):
+	}
+	m.Detach(id)
+	if got := collect(t, m.Out()); len(got) != 0 {
+		t.Errorf("got: %v", got)
+	}
+}
+
+func TestMux_NilSourceOrdered(t *testing.T) {
+	m := New[int](Ordered, 0)
+	defer m.Stop()
+	if _, err := m.Attach(nil); !errors.Is(err, ErrNilSource) {
+		t.Errorf("expected ErrNilSource, got: %v", err)
+	}
+	id, _ := m.Attach(make(chan int))
+	if m.Swap(id, nil) {
+		t.Error("nil swap accepted")
+	}
+}
+
+func TestMux_SwapWhileRanging(t *testing.T) {
+	// The equivalent of reassigning ch in the range loop
+	ch1 := make(chan int)
+	ch2 := produce(10, 11, 12)
+	m := New[int](Ordered, 0)
+	id, err := m.Attach(ch1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.Seal()
+
+	go func() { ch1 <- 0 }()
+	var got []int
+	for v := range m.Out() {
+		got = append(got, v)
+		if v == 0 {
+			m.Swap(id, ch2)
+		}
+	}
+	if expected := []int{0, 10, 11, 12}; !reflect.DeepEqual(got, expected) {
+		t.Errorf("got: %v, expected: %v", got, expected)
+	}
+	if m.Swap(id, ch1) || m.Detach(id) {
+		t.Error("source still attached")
+	}
+}
+
+func TestMux_AttachDetach(t *testing.T) {
+	m := New[int](Fair, 0)
+	ch1 := make(chan int)
+	ch2 := make(chan int)
+	id1, _ := m.Attach(ch1)
+	_, _ = m.Attach(ch2)
+
+	ch1 <- 1
+	if v := <-m.Out(); v != 1 {
+		t.Errorf("got: %d", v)
+	}
+	if !m.Detach(id1) {
+		t.Fatal("not detached")
+	}
+	// ch1 isn't read anymore
+	select {
+	case ch1 <- 2:
+		t.Fatal("detached source read")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	ch2 <- 3
+	if v := <-m.Out(); v != 3 {
+		t.Errorf("got: %d", v)
+	}
+
+	m.Seal()
+	if _, err := m.Attach(ch1); !errors.Is(err, ErrClosed) {
+		t.Errorf("expected ErrClosed, got: %v", err)
+	}
+	close(ch2)
+	if got := collect(t, m.Out()); len(got) != 0 {
+		t.Errorf("got: %v", got)
+	}
+	if _, err := m.Attach(ch1); !errors.Is(err, ErrClosed) {
+		t.Errorf("expected ErrClosed, got: %v", err)
+	}
+}
+
+func TestMux_Stop(t *testing.T) {
+	m := New[int](Fair, 0)
+	ch := make(chan int, 1)
+	ch <- 1
+	_, _ = m.Attach(ch)
+	// Wait until the value is read and pending
+	for len(ch) != 0 {
+		time.Sleep(time.Millisecond)
+	}
+	m.Stop()
+	if got := collect(t, m.Out()); !reflect.DeepEqual(got, []int{1}) {
+		t.Errorf("got: %v", got)
+	}
+}
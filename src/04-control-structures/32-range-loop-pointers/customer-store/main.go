@@ -1,43 +1,58 @@
 package main
 
-import "fmt"
+import (
+	"fmt"
+
+	"github.com/teivah/100-go-mistakes/src/04-control-structures/32-range-loop-pointers/customer-store/store"
+)
 
 type Customer struct {
 	ID      string
 	Balance float64
 }
 
-type Store struct {
+// pointerStore keeps pointers to the customers; see store.Store for a
+// registry storing copies.
+type pointerStore struct {
 	m map[string]*Customer
 }
 
 func main() {
-	s := Store{
-		m: make(map[string]*Customer),
-	}
-	s.storeCustomers([]Customer{
+	customers := []Customer{
 		{ID: "1", Balance: 10},
 		{ID: "2", Balance: -10},
 		{ID: "3", Balance: 0},
-	})
+	}
+
+	s := pointerStore{
+		m: make(map[string]*Customer),
+	}
+	s.storeCustomers(customers)
 	print(s.m)
+
+	registry := store.NewStore()
+	for _, customer := range customers {
+		_ = registry.Put(store.Customer(customer))
+	}
+	negatives, _ := registry.Find(store.IndexBalanceSign, "negative")
+	fmt.Printf("customers=%v, negative balances=%v\n", registry.List(), negatives)
 }
 
-func (s *Store) storeCustomers(customers []Customer) {
+func (s *pointerStore) storeCustomers(customers []Customer) {
 	for _, customer := range customers {
 		fmt.Printf("%p\n", &customer)
 		s.m[customer.ID] = &customer
 	}
 }
 
-func (s *Store) storeCustomers2(customers []Customer) {
+func (s *pointerStore) storeCustomers2(customers []Customer) {
 	for _, customer := range customers {
 		current := customer
 		s.m[current.ID] = &current
 	}
 }
 
-func (s *Store) storeCustomers3(customers []Customer) {
+func (s *pointerStore) storeCustomers3(customers []Customer) {
 	for i := range customers {
 		customer := &customers[i]
 		s.m[customer.ID] = customer
@@ -0,0 +1,196 @@
+// Package store provides a customer registry storing copies of the
+// customers, so that it never aliases the caller's memory.
+package store
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+type Customer struct {
+	ID      string
+	Balance float64
+}
+
+// IndexBalanceSign is the name of the index created by NewStore, grouping the
+// customers under the keys "negative", "zero" and "positive".
+const IndexBalanceSign = "balance-sign"
+
+var (
+	ErrEmptyID      = errors.New("empty customer ID")
+	ErrIndexExists  = errors.New("index already exists")
+	ErrUnknownIndex = errors.New("unknown index")
+)
+
+// IndexFunc returns the key under which a customer is indexed; an empty key
+// leaves the customer out of the index.
+type IndexFunc func(Customer) string
+
+// BalanceSign indexes the customers by the sign of their balance.
+func BalanceSign(c Customer) string {
+	switch {
+	case c.Balance < 0:
+		return "negative"
+	case c.Balance > 0:
+		return "positive"
+	}
+	return "zero"
+}
+
+type index struct {
+	key  IndexFunc
+	keys map[string]map[string]struct{}
+}
+
+func (idx *index) add(c Customer) {
+	k := idx.key(c)
+	if k == "" {
+		return
+	}
+	ids, ok := idx.keys[k]
+	if !ok {
+		ids = make(map[string]struct{})
+		idx.keys[k] = ids
+	}
+	ids[c.ID] = struct{}{}
+}
+
+func (idx *index) remove(c Customer) {
+	k := idx.key(c)
+	ids := idx.keys[k]
+	delete(ids, c.ID)
+	if len(ids) == 0 {
+		delete(idx.keys, k)
+	}
+}
+
+// Store is a customer registry safe for concurrent use. It stores copies of
+// the customers and returns copies, so that it never aliases the memory of
+// its callers, unlike the pointers kept by storeCustomers.
+type Store struct {
+	mu      sync.RWMutex
+	m       map[string]Customer
+	indexes map[string]*index
+}
+
+func NewStore() *Store {
+	s := &Store{
+		m:       make(map[string]Customer),
+		indexes: make(map[string]*index),
+	}
+	_ = s.AddIndex(IndexBalanceSign, BalanceSign)
+	return s
+}
+
+// AddIndex creates an index, built from the customers already stored.
+func (s *Store) AddIndex(name string, key IndexFunc) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, exists := s.indexes[name]; exists {
+		return fmt.Errorf("%w: %s", ErrIndexExists, name)
+	}
+	idx := &index{key: key, keys: make(map[string]map[string]struct{})}
+	for _, c := range s.m {
+		idx.add(c)
+	}
+	s.indexes[name] = idx
+	return nil
+}
+
+// Put adds a customer or replaces the one with the same ID.
+func (s *Store) Put(c Customer) error {
+	if c.ID == "" {
+		return ErrEmptyID
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.put(c)
+	return nil
+}
+
+// PutAll adds all the customers, or none of them if one is invalid.
+func (s *Store) PutAll(customers []Customer) error {
+	for _, c := range customers {
+		if c.ID == "" {
+			return ErrEmptyID
+		}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, c := range customers {
+		s.put(c)
+	}
+	return nil
+}
+
+func (s *Store) put(c Customer) {
+	if previous, exists := s.m[c.ID]; exists {
+		for _, idx := range s.indexes {
+			idx.remove(previous)
+		}
+	}
+	s.m[c.ID] = c
+	for _, idx := range s.indexes {
+		idx.add(c)
+	}
+}
+
+func (s *Store) Get(id string) (Customer, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	c, ok := s.m[id]
+	return c, ok
+}
+
+// List returns all the customers sorted by ID.
+func (s *Store) List() []Customer {
+	s.mu.RLock()
+	customers := make([]Customer, 0, len(s.m))
+	for _, c := range s.m {
+		customers = append(customers, c)
+	}
+	s.mu.RUnlock()
+	sortByID(customers)
+	return customers
+}
+
+// Delete removes a customer and reports whether it existed.
+func (s *Store) Delete(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c, exists := s.m[id]
+	if !exists {
+		return false
+	}
+	for _, idx := range s.indexes {
+		idx.remove(c)
+	}
+	delete(s.m, id)
+	return true
+}
+
+// Find returns the customers indexed under key, sorted by ID.
+func (s *Store) Find(indexName, key string) ([]Customer, error) {
+	s.mu.RLock()
+	idx, ok := s.indexes[indexName]
+	if !ok {
+		s.mu.RUnlock()
+		return nil, fmt.Errorf("%w: %s", ErrUnknownIndex, indexName)
+	}
+	ids := idx.keys[key]
+	customers := make([]Customer, 0, len(ids))
+	for id := range ids {
+		customers = append(customers, s.m[id])
+	}
+	s.mu.RUnlock()
+	sortByID(customers)
+	return customers, nil
+}
+
+func sortByID(customers []Customer) {
+	sort.Slice(customers, func(i, j int) bool {
+		return customers[i].ID < customers[j].ID
+	})
+}
@@ -0,0 +1,157 @@
+package store
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestStore_CopiesValues(t *testing.T) {
+	s := NewStore()
+	customers := []Customer{
+		{ID: "1", Balance: 10},
+		{ID: "2", Balance: -10},
+	}
+	if err := s.PutAll(customers); err != nil {
+		t.Fatal(err)
+	}
+	customers[0].Balance = 100
+
+	c, ok := s.Get("1")
+	if !ok || c.Balance != 10 {
+		t.Fatalf("caller memory aliased: %+v, %t", c, ok)
+	}
+	c.Balance = 200
+	if c, _ := s.Get("1"); c.Balance != 10 {
+		t.Errorf("returned value aliased: %+v", c)
+	}
+	list := s.List()
+	list[1].Balance = 300
+	if c, _ := s.Get("2"); c.Balance != -10 {
+		t.Errorf("list aliased: %+v", c)
+	}
+}
+
+func TestStore_PutGetListDelete(t *testing.T) {
+	s := NewStore()
+	for _, c := range []Customer{{ID: "b", Balance: 1}, {ID: "a", Balance: 2}} {
+		if err := s.Put(c); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.Put(Customer{}); !errors.Is(err, ErrEmptyID) {
+		t.Errorf("expected ErrEmptyID, got: %v", err)
+	}
+	if err := s.PutAll([]Customer{{ID: "c"}, {}}); !errors.Is(err, ErrEmptyID) {
+		t.Errorf("expected ErrEmptyID, got: %v", err)
+	}
+	if _, ok := s.Get("c"); ok {
+		t.Error("partial PutAll")
+	}
+
+	expected := []Customer{{ID: "a", Balance: 2}, {ID: "b", Balance: 1}}
+	if got := s.List(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("got: %v, expected: %v", got, expected)
+	}
+	if !s.Delete("a") || s.Delete("a") {
+		t.Error("unexpected Delete result")
+	}
+	if _, ok := s.Get("a"); ok {
+		t.Error("customer not deleted")
+	}
+}
+
+func TestStore_Indexes(t *testing.T) {
+	s := NewStore()
+	_ = s.PutAll([]Customer{
+		{ID: "1", Balance: 10},
+		{ID: "2", Balance: -10},
+		{ID: "3", Balance: -5},
+		{ID: "4", Balance: 0},
+	})
+	if err := s.AddIndex("rich", func(c Customer) string {
+		if c.Balance >= 10 {
+			return "yes"
+		}
+		return ""
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.AddIndex(IndexBalanceSign, BalanceSign); !errors.Is(err, ErrIndexExists) {
+		t.Errorf("expected ErrIndexExists, got: %v", err)
+	}
+
+	find := func(index, key string) []Customer {
+		t.Helper()
+		customers, err := s.Find(index, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return customers
+	}
+
+	if got := find(IndexBalanceSign, "negative"); !reflect.DeepEqual(got, []Customer{{"2", -10}, {"3", -5}}) {
+		t.Errorf("negative: %v", got)
+	}
+	if got := find("rich", "yes"); !reflect.DeepEqual(got, []Customer{{"1", 10}}) {
+		t.Errorf("rich: %v", got)
+	}
+
+	// Updating and deleting keep the indexes in sync
+	_ = s.Put(Customer{ID: "2", Balance: 20})
+	s.Delete("3")
+	if got := find(IndexBalanceSign, "negative"); len(got) != 0 {
+		t.Errorf("negative: %v", got)
+	}
+	if got := find("rich", "yes"); !reflect.DeepEqual(got, []Customer{{"1", 10}, {"2", 20}}) {
+		t.Errorf("rich: %v", got)
+	}
+
+	if _, err := s.Find("unknown", ""); !errors.Is(err, ErrUnknownIndex) {
+		t.Errorf("expected ErrUnknownIndex, got: %v", err)
+	}
+}
+
+// TestStore_Concurrent is meant to be run with the race detector.
+func TestStore_Concurrent(t *testing.T) {
+	s := NewStore()
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		g := g
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				id := strconv.Itoa(g*1000 + i)
+				_ = s.Put(Customer{ID: id, Balance: float64(i - 100)})
+				s.Get(id)
+				s.List()
+				_, _ = s.Find(IndexBalanceSign, "negative")
+				if i%2 == 0 {
+					s.Delete(id)
+				}
+			}
+		}()
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_ = s.AddIndex("even", func(c Customer) string {
+			if int(c.Balance)%2 == 0 {
+				return "even"
+			}
+			return ""
+		})
+	}()
+	wg.Wait()
+
+	if got := len(s.List()); got != 8*100 {
+		t.Errorf("got %d customers", got)
+	}
+	negatives, _ := s.Find(IndexBalanceSign, "negative")
+	if len(negatives) != 8*50 {
+		t.Errorf("got %d negative balances", len(negatives))
+	}
+}
@@ -1,6 +1,10 @@
 package main
 
-import "fmt"
+import (
+	"fmt"
+
+	"github.com/teivah/100-go-mistakes/src/04-control-structures/33-map-iteration/orderedmap"
+)
 
 func listing1() {
 	m := map[int]bool{
@@ -36,6 +40,23 @@ func listing2() {
 	fmt.Println(m2)
 }
 
+func listing3() {
+	m := orderedmap.New[int, bool]()
+	m.Set(0, true)
+	m.Set(1, false)
+	m.Set(2, true)
+
+	// Range iterates over a snapshot, so the insertions are never visited
+	m.Range(func(k int, v bool) bool {
+		if v {
+			m.Set(10+k, true)
+		}
+		return true
+	})
+
+	fmt.Println(m.Snapshot().Keys())
+}
+
 func copyMap(m map[int]bool) map[int]bool {
 	res := make(map[int]bool, len(m))
 	for k, v := range m {
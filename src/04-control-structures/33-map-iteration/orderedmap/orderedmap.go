@@ -0,0 +1,205 @@
+// Package orderedmap provides a map iterated in a deterministic order, which
+// can be mutated while being iterated over.
+package orderedmap
+
+import "sort"
+
+// state holds the entries of a map. Once frozen by a snapshot, it is never
+// mutated again: the map copies it on the next write.
+type state[K comparable, V any] struct {
+	keys   []K
+	values map[K]V
+	frozen bool
+}
+
+func (s *state[K, V]) clone() *state[K, V] {
+	values := make(map[K]V, len(s.values))
+	for k, v := range s.values {
+		values[k] = v
+	}
+	return &state[K, V]{
+		keys:   append(make([]K, 0, len(s.keys)), s.keys...),
+		values: values,
+	}
+}
+
+// Map is a map iterated in insertion order, or in key order if created with
+// NewSorted. Iterating works on a snapshot, so the map can be mutated in the
+// meantime: the writes are visible to the next iterations only.
+//
+// Set on a new key and Delete are O(n) as the ordered keys are kept in a
+// slice. A Map isn't safe for concurrent use.
+type Map[K comparable, V any] struct {
+	s    *state[K, V]
+	less func(a, b K) bool
+}
+
+// New returns a map iterated in insertion order. Setting an existing key
+// doesn't change its position.
+func New[K comparable, V any]() *Map[K, V] {
+	return &Map[K, V]{s: &state[K, V]{values: make(map[K]V)}}
+}
+
+// NewSorted returns a map iterated in the order defined by less.
+func NewSorted[K comparable, V any](less func(a, b K) bool) *Map[K, V] {
+	m := New[K, V]()
+	m.less = less
+	return m
+}
+
+// writable returns the state, copied first if a snapshot shares it.
+func (m *Map[K, V]) writable() *state[K, V] {
+	if m.s.frozen {
+		m.s = m.s.clone()
+	}
+	return m.s
+}
+
+func (m *Map[K, V]) Get(k K) (V, bool) {
+	v, ok := m.s.values[k]
+	return v, ok
+}
+
+func (m *Map[K, V]) Len() int {
+	return len(m.s.keys)
+}
+
+func (m *Map[K, V]) Set(k K, v V) {
+	s := m.writable()
+	if _, exists := s.values[k]; !exists {
+		i := len(s.keys)
+		if m.less != nil {
+			i = sort.Search(len(s.keys), func(i int) bool {
+				return m.less(k, s.keys[i])
+			})
+		}
+		var zero K
+		s.keys = append(s.keys, zero)
+		copy(s.keys[i+1:], s.keys[i:])
+		s.keys[i] = k
+	}
+	s.values[k] = v
+}
+
+// Delete removes k and reports whether it existed.
+func (m *Map[K, V]) Delete(k K) bool {
+	if _, exists := m.s.values[k]; !exists {
+		return false
+	}
+	s := m.writable()
+	delete(s.values, k)
+	for i, key := range s.keys {
+		if key == k {
+			copy(s.keys[i:], s.keys[i+1:])
+			// Otherwise, the backing array would keep the last key alive
+			var zero K
+			s.keys[len(s.keys)-1] = zero
+			s.keys = s.keys[:len(s.keys)-1]
+			break
+		}
+	}
+	return true
+}
+
+// Snapshot returns a read-only view of the current entries. It costs nothing
+// until the map is written to, which then copies the entries once.
+func (m *Map[K, V]) Snapshot() *Snapshot[K, V] {
+	m.s.frozen = true
+	return &Snapshot[K, V]{s: m.s}
+}
+
+// Range calls f for each entry of a snapshot taken beforehand, until f
+// returns false. f may mutate the map.
+func (m *Map[K, V]) Range(f func(k K, v V) bool) {
+	m.Snapshot().Range(f)
+}
+
+// Cursor returns a cursor over a snapshot of the map.
+func (m *Map[K, V]) Cursor() *Cursor[K, V] {
+	return m.Snapshot().Cursor()
+}
+
+// Snapshot is an immutable view of a map.
+type Snapshot[K comparable, V any] struct {
+	s *state[K, V]
+}
+
+func (s *Snapshot[K, V]) Get(k K) (V, bool) {
+	v, ok := s.s.values[k]
+	return v, ok
+}
+
+func (s *Snapshot[K, V]) Len() int {
+	return len(s.s.keys)
+}
+
+func (s *Snapshot[K, V]) Range(f func(k K, v V) bool) {
+	for _, k := range s.s.keys {
+		if !f(k, s.s.values[k]) {
+			return
+		}
+	}
+}
+
+// Keys returns the keys in order.
+func (s *Snapshot[K, V]) Keys() []K {
+	return append([]K(nil), s.s.keys...)
+}
+
+func (s *Snapshot[K, V]) Cursor() *Cursor[K, V] {
+	return &Cursor[K, V]{s: s.s, i: -1}
+}
+
+// Cursor walks through a snapshot in both directions. It starts before the
+// first entry:
+//
+//	c := m.Cursor()
+//	for c.Next() {
+//		fmt.Println(c.Key(), c.Value())
+//	}
+type Cursor[K comparable, V any] struct {
+	s *state[K, V]
+	i int
+}
+
+// Next moves to the next entry and reports whether there is one.
+func (c *Cursor[K, V]) Next() bool {
+	if c.i < len(c.s.keys) {
+		c.i++
+	}
+	return c.i < len(c.s.keys)
+}
+
+// Prev moves to the previous entry and reports whether there is one.
+func (c *Cursor[K, V]) Prev() bool {
+	if c.i >= 0 {
+		c.i--
+	}
+	return c.i >= 0
+}
+
+// Seek moves to k and reports whether it exists in the snapshot.
+func (c *Cursor[K, V]) Seek(k K) bool {
+	if _, exists := c.s.values[k]; !exists {
+		return false
+	}
+	for i, key := range c.s.keys {
+		if key == k {
+			c.i = i
+			break
+		}
+	}
+	return true
+}
+
+// Key returns the key of the current entry. It panics if the cursor isn't
+// on an entry.
+func (c *Cursor[K, V]) Key() K {
+	return c.s.keys[c.i]
+}
+
+// Value returns the value of the current entry. It panics if the cursor
+// isn't on an entry.
+func (c *Cursor[K, V]) Value() V {
+	return c.s.values[c.s.keys[c.i]]
+}
@@ -0,0 +1,214 @@
+package orderedmap
+
+import (
+	"reflect"
+	"testing"
+)
+
+func less(a, b int) bool { return a < b }
+
+func keys[K comparable, V any](m *Map[K, V]) []K {
+	return m.Snapshot().Keys()
+}
+
+func TestMap_InsertDuringRange(t *testing.T) {
+	// Same as listing1, which is nondeterministic with a built-in map
+	for i := 0; i < 100; i++ {
+		m := New[int, bool]()
+		m.Set(0, true)
+		m.Set(1, false)
+		m.Set(2, true)
+
+		m.Range(func(k int, v bool) bool {
+			if v {
+				m.Set(10+k, true)
+			}
+			return true
+		})
+
+		if got := keys(m); !reflect.DeepEqual(got, []int{0, 1, 2, 10, 12}) {
+			t.Fatalf("got: %v", got)
+		}
+	}
+}
+
+func TestMap_Order(t *testing.T) {
+	inserted := New[int, string]()
+	sorted := NewSorted[int, string](less)
+	for _, k := range []int{3, 1, 2} {
+		inserted.Set(k, "")
+		sorted.Set(k, "")
+	}
+	inserted.Set(3, "updated")
+
+	if got := keys(inserted); !reflect.DeepEqual(got, []int{3, 1, 2}) {
+		t.Errorf("insertion order: %v", got)
+	}
+	if got := keys(sorted); !reflect.DeepEqual(got, []int{1, 2, 3}) {
+		t.Errorf("key order: %v", got)
+	}
+	if v, _ := inserted.Get(3); v != "updated" {
+		t.Errorf("got: %s", v)
+	}
+}
+
+func TestMap_DeleteDuringRange(t *testing.T) {
+	m := NewSorted[int, int](less)
+	for i := 0; i < 10; i++ {
+		m.Set(i, i)
+	}
+	var visited []int
+	m.Range(func(k, v int) bool {
+		visited = append(visited, k)
+		m.Delete(k + 1)
+		return true
+	})
+	if len(visited) != 10 {
+		t.Errorf("snapshot altered: %v", visited)
+	}
+	if m.Len() != 1 {
+		t.Errorf("got: %v", keys(m))
+	}
+	if m.Delete(5) {
+		t.Error("deleted twice")
+	}
+}
+
+func TestMap_DeleteReleasesKey(t *testing.T) {
+	m := New[*int, int]()
+	a, b := new(int), new(int)
+	m.Set(a, 1)
+	m.Set(b, 2)
+	m.Delete(a)
+	if last := m.s.keys[:2][1]; last != nil {
+		t.Error("the deleted slot still references a key")
+	}
+}
+
+func TestSnapshot_Isolation(t *testing.T) {
+	m := New[string, int]()
+	m.Set("a", 1)
+	s := m.Snapshot()
+	m.Set("a", 2)
+	m.Set("b", 3)
+	s2 := m.Snapshot()
+	m.Delete("a")
+
+	if v, _ := s.Get("a"); v != 1 || s.Len() != 1 {
+		t.Errorf("first snapshot: %v", s.Keys())
+	}
+	if v, _ := s2.Get("a"); v != 2 || s2.Len() != 2 {
+		t.Errorf("second snapshot: %v", s2.Keys())
+	}
+	if _, ok := m.Get("a"); ok || m.Len() != 1 {
+		t.Errorf("map: %v", keys(m))
+	}
+}
+
+func TestCursor(t *testing.T) {
+	m := NewSorted[int, string](less)
+	m.Set(2, "b")
+	m.Set(1, "a")
+	m.Set(3, "c")
+
+	c := m.Cursor()
+	m.Set(0, "inserted after the cursor creation")
+	var got []string
+	for c.Next() {
+		got = append(got, c.Value())
+	}
+	if !reflect.DeepEqual(got, []string{"a", "b", "c"}) {
+		t.Errorf("forward: %v", got)
+	}
+	if c.Next() {
+		t.Error("Next after the end")
+	}
+
+	got = nil
+	for c.Prev() {
+		got = append(got, c.Value())
+	}
+	if !reflect.DeepEqual(got, []string{"c", "b", "a"}) {
+		t.Errorf("backward: %v", got)
+	}
+
+	if !c.Seek(2) || c.Key() != 2 || !c.Next() || c.Key() != 3 {
+		t.Error("seek to an existing key")
+	}
+	if c.Seek(0) {
+		t.Error("seek to a key missing from the snapshot")
+	}
+}
+
+const benchSize = 1000
+
+func benchMap() map[int]bool {
+	m := make(map[int]bool, benchSize)
+	for i := 0; i < benchSize; i++ {
+		m[i] = i%2 == 0
+	}
+	return m
+}
+
+func copyMap(m map[int]bool) map[int]bool {
+	res := make(map[int]bool, len(m))
+	for k, v := range m {
+		res[k] = v
+	}
+	return res
+}
+
+var global int
+
+func BenchmarkCopyMap(b *testing.B) {
+	m := benchMap()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m2 := copyMap(m)
+		for k, v := range m {
+			if v {
+				m2[benchSize+k] = true
+			}
+		}
+		global = len(m2)
+	}
+}
+
+func BenchmarkOrderedMap(b *testing.B) {
+	src := New[int, bool]()
+	for k, v := range benchMap() {
+		src.Set(k, v)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		// Rebuilt through a snapshot to start from the same entries
+		m := New[int, bool]()
+		m.s = src.Snapshot().s
+		m.Range(func(k int, v bool) bool {
+			if v {
+				m.Set(benchSize+k, true)
+			}
+			return true
+		})
+		global = m.Len()
+	}
+}
+
+func BenchmarkOrderedMap_SortedKeys(b *testing.B) {
+	src := NewSorted[int, bool](less)
+	for k, v := range benchMap() {
+		src.Set(k, v)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m := NewSorted[int, bool](less)
+		m.s = src.Snapshot().s
+		m.Range(func(k int, v bool) bool {
+			if v {
+				m.Set(benchSize+k, true)
+			}
+			return true
+		})
+		global = m.Len()
+	}
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/teivah/100-go-mistakes/src/05-strings/37-string-iteration/runeindex"
+)
+
+var (
+	largeString = strings.Repeat("hêllo, 世界! ", 10_000)
+	global      rune
+)
+
+// lookups returns the rune positions and their byte offsets, as getIthRune
+// takes a byte offset.
+func lookups() ([]int, []int) {
+	var positions, offsets []int
+	i := 0
+	for off := range largeString {
+		if i%997 == 0 {
+			positions = append(positions, i)
+			offsets = append(offsets, off)
+		}
+		i++
+	}
+	return positions, offsets
+}
+
+func BenchmarkGetIthRune(b *testing.B) {
+	_, offsets := lookups()
+	var local rune
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, off := range offsets {
+			local = getIthRune(largeString, off)
+		}
+	}
+	global = local
+}
+
+func BenchmarkRuneAt(b *testing.B) {
+	positions, _ := lookups()
+	x := runeindex.New(largeString)
+	var local rune
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, pos := range positions {
+			local = x.RuneAt(pos)
+		}
+	}
+	global = local
+}
+
+func BenchmarkRuneSliceConversion(b *testing.B) {
+	positions, _ := lookups()
+	var local rune
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		runes := []rune(largeString)
+		for _, pos := range positions {
+			local = runes[pos]
+		}
+	}
+	global = local
+}
+
+func BenchmarkNew(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_ = runeindex.New(largeString)
+	}
+}
@@ -0,0 +1,53 @@
+package runeindex
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+const zwj = '\u200d'
+
+// graphemeBoundary reports whether a grapheme cluster may end at off, which
+// must be a rune boundary strictly inside s.
+//
+// It implements the subset of the Unicode rules (UAX #29) needed by common
+// texts: CR LF, combining marks, variation selectors, emoji modifiers and
+// tags, zero width joiner sequences and regional indicator (flag) pairs.
+// The Hangul syllable and prepend rules aren't implemented.
+func graphemeBoundary(s string, off int) bool {
+	prev, _ := utf8.DecodeLastRuneInString(s[:off])
+	next, _ := utf8.DecodeRuneInString(s[off:])
+
+	switch {
+	case prev == '\r' && next == '\n':
+		return false
+	case extend(next) || next == zwj:
+		return false
+	case prev == zwj:
+		return false
+	case regionalIndicator(prev) && regionalIndicator(next):
+		// Flags are pairs: break after an even number of indicators
+		count := 0
+		for end := off; end > 0; {
+			r, size := utf8.DecodeLastRuneInString(s[:end])
+			if !regionalIndicator(r) {
+				break
+			}
+			count++
+			end -= size
+		}
+		return count%2 == 0
+	}
+	return true
+}
+
+func extend(r rune) bool {
+	return unicode.In(r, unicode.Mn, unicode.Me, unicode.Mc) ||
+		unicode.Is(unicode.Variation_Selector, r) ||
+		(r >= 0x1f3fb && r <= 0x1f3ff) || // emoji modifiers
+		(r >= 0xe0020 && r <= 0xe007f) // tags
+}
+
+func regionalIndicator(r rune) bool {
+	return r >= 0x1f1e6 && r <= 0x1f1ff
+}
@@ -0,0 +1,126 @@
+// Package runeindex gives access to the runes of a string by position,
+// without converting it into a []rune.
+//
+// A String records the byte offset of every stride-th rune, so finding a
+// rune decodes at most stride-1 runes instead of scanning from the start.
+package runeindex
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// DefaultStride trades the index size (a 4-byte offset per stride runes)
+// against the lookup cost.
+const DefaultStride = 64
+
+// String is an immutable string indexed by rune position. As with a range
+// loop, each invalid UTF-8 byte counts as one utf8.RuneError.
+type String struct {
+	s       string
+	n       int
+	stride  int
+	offsets []int32
+}
+
+func New(s string) *String {
+	return NewStride(s, DefaultStride)
+}
+
+// NewStride indexes s with an offset every stride runes. It panics if
+// stride isn't strictly positive or if s is larger than 2 GB.
+func NewStride(s string, stride int) *String {
+	if stride <= 0 {
+		panic("runeindex: stride should be strictly positive")
+	}
+	if int64(len(s)) > 1<<31-1 {
+		panic("runeindex: string too large")
+	}
+	idx := &String{s: s, stride: stride}
+	for off := 0; off < len(s); {
+		if idx.n%stride == 0 {
+			idx.offsets = append(idx.offsets, int32(off))
+		}
+		if s[off] < utf8.RuneSelf {
+			off++
+		} else {
+			_, size := utf8.DecodeRuneInString(s[off:])
+			off += size
+		}
+		idx.n++
+	}
+	if idx.n == len(s) {
+		// ASCII only: the rune positions are the byte offsets
+		idx.offsets = nil
+	}
+	return idx
+}
+
+// Len returns the number of runes.
+func (x *String) Len() int {
+	return x.n
+}
+
+func (x *String) String() string {
+	return x.s
+}
+
+// Offset returns the byte offset of the ith rune; i may be Len, for the end
+// of the string.
+func (x *String) Offset(i int) int {
+	if i < 0 || i > x.n {
+		panic(fmt.Sprintf("runeindex: index %d out of range [0:%d]", i, x.n))
+	}
+	if x.offsets == nil {
+		return i
+	}
+	if i == x.n {
+		return len(x.s)
+	}
+	off := int(x.offsets[i/x.stride])
+	for k := i % x.stride; k > 0; k-- {
+		if x.s[off] < utf8.RuneSelf {
+			off++
+		} else {
+			_, size := utf8.DecodeRuneInString(x.s[off:])
+			off += size
+		}
+	}
+	return off
+}
+
+// RuneAt returns the ith rune. It panics if i is out of range.
+func (x *String) RuneAt(i int) rune {
+	if i < 0 || i >= x.n {
+		panic(fmt.Sprintf("runeindex: index %d out of range [0:%d]", i, x.n))
+	}
+	r, _ := utf8.DecodeRuneInString(x.s[x.Offset(i):])
+	return r
+}
+
+// Slice returns the runes from position i to j excluded. Like any substring,
+// the result shares the memory of the whole string; use strings.Clone to
+// keep a small part of a large string.
+func (x *String) Slice(i, j int) string {
+	if i > j {
+		panic(fmt.Sprintf("runeindex: invalid slice indices %d > %d", i, j))
+	}
+	return x.s[x.Offset(i):x.Offset(j)]
+}
+
+// Truncate returns at most the first n runes, without splitting a
+// grapheme cluster: a cluster crossing the limit is dropped entirely.
+func (x *String) Truncate(n int) string {
+	if n < 0 {
+		panic(fmt.Sprintf("runeindex: negative length %d", n))
+	}
+	if n >= x.n {
+		return x.s
+	}
+	off := x.Offset(n)
+	for off > 0 && !graphemeBoundary(x.s, off) {
+		_, size := utf8.DecodeLastRuneInString(x.s[:off])
+		off -= size
+	}
+	return x.s[:off]
+}
@@ -0,0 +1,114 @@
+package runeindex
+
+import (
+	"strings"
+	"testing"
+	"testing/quick"
+	"unicode/utf8"
+)
+
+func TestString_MatchesRunes(t *testing.T) {
+	inputs := []string{
+		"",
+		"hello",
+		"hêllo, 世界",
+		strings.Repeat("aé世🙂", 100),
+		"invalid \xff\xfe utf-8",
+	}
+	for _, s := range inputs {
+		for _, stride := range []int{1, 3, DefaultStride} {
+			checkString(t, s, stride)
+		}
+	}
+}
+
+func checkString(t *testing.T, s string, stride int) {
+	t.Helper()
+	x := NewStride(s, stride)
+	runes := []rune(s)
+	if x.Len() != len(runes) {
+		t.Fatalf("%q: len %d, expected %d", s, x.Len(), len(runes))
+	}
+	for i, r := range runes {
+		if got := x.RuneAt(i); got != r {
+			t.Fatalf("%q, stride %d: rune %d: got %q, expected %q", s, stride, i, got, r)
+		}
+	}
+	for i := 0; i <= len(runes); i++ {
+		for j := i; j <= len(runes) && j < i+5; j++ {
+			if got, expected := x.Slice(i, j), string(runes[i:j]); got != expected && utf8.ValidString(s) {
+				t.Fatalf("%q: slice [%d:%d]: got %q, expected %q", s, i, j, got, expected)
+			}
+		}
+	}
+}
+
+func TestString_Quick(t *testing.T) {
+	f := func(s string, stride uint8) bool {
+		x := NewStride(s, int(stride%16)+1)
+		runes := []rune(s)
+		if x.Len() != len(runes) {
+			return false
+		}
+		for i, r := range runes {
+			if x.RuneAt(i) != r {
+				return false
+			}
+		}
+		return x.Slice(0, x.Len()) == s
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestString_OutOfRange(t *testing.T) {
+	x := New("hêllo")
+	for name, f := range map[string]func(){
+		"rune at len":    func() { x.RuneAt(5) },
+		"negative rune":  func() { x.RuneAt(-1) },
+		"slice past len": func() { x.Slice(0, 6) },
+		"reversed slice": func() { x.Slice(3, 2) },
+		"bad stride":     func() { NewStride("", 0) },
+	} {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("expected a panic")
+				}
+			}()
+			f()
+		})
+	}
+}
+
+func TestString_Truncate(t *testing.T) {
+	tests := []struct {
+		s        string
+		n        int
+		expected string
+	}{
+		{"hello", 3, "hel"},
+		{"hello", 10, "hello"},
+		{"hêllo", 2, "hê"},
+		// e followed by a combining circumflex
+		{"he\u0302llo", 2, "h"},
+		{"he\u0302llo", 3, "he\u0302"},
+		{"a\r\nb", 2, "a"},
+		// Thumbs up with a skin tone modifier
+		{"ok👍🏽", 3, "ok"},
+		// Family: man, ZWJ, woman, ZWJ, girl
+		{"x👨\u200d👩\u200d👧", 4, "x"},
+		{"x👨\u200d👩\u200d👧", 6, "x👨\u200d👩\u200d👧"},
+		// Flags: France then Japan
+		{"🇫🇷🇯🇵", 1, ""},
+		{"🇫🇷🇯🇵", 2, "🇫🇷"},
+		{"🇫🇷🇯🇵", 3, "🇫🇷"},
+		{"", 0, ""},
+	}
+	for _, tt := range tests {
+		if got := New(tt.s).Truncate(tt.n); got != tt.expected {
+			t.Errorf("Truncate(%q, %d): got %q, expected %q", tt.s, tt.n, got, tt.expected)
+		}
+	}
+}
@@ -3,6 +3,8 @@ package main
 import (
 	"fmt"
 	"strings"
+
+	"github.com/teivah/100-go-mistakes/src/05-strings/38-trim/sanitize"
 )
 
 func main() {
@@ -14,4 +16,7 @@ func main() {
 	fmt.Println(strings.TrimPrefix("oxo123", "ox"))
 
 	fmt.Println(strings.Trim("oxo123oxo", "ox"))
+
+	// Repeated prefixes, not a set of characters
+	fmt.Println(sanitize.TrimPrefixes("oxox123", "ox"))
 }
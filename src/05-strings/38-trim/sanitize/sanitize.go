@@ -0,0 +1,277 @@
+// Package sanitize cleans up text held either in a string or in a []byte,
+// without the caller converting between them.
+//
+// The functions never modify their input. The result may share the memory
+// of the input, for example when nothing needs to be removed.
+package sanitize
+
+import (
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Text is implemented by strings and byte slices.
+type Text interface {
+	~string | ~[]byte
+}
+
+// Newline is a line ending.
+type Newline string
+
+const (
+	LF   Newline = "\n"
+	CRLF Newline = "\r\n"
+	CR   Newline = "\r"
+)
+
+// Options configures Sanitize. The steps run in the order of the fields.
+type Options struct {
+	// StripControl removes the control characters, except those in
+	// KeepControl (e.g. "\n\t").
+	StripControl bool
+	KeepControl  string
+	// Newline, if set, replaces every line ending.
+	Newline Newline
+	// NFC normalizes the text into the Unicode composed form. It runs after
+	// StripControl, as removing a control character may bring together a
+	// letter and a combining mark.
+	NFC bool
+	// TrimSpace removes the leading and trailing white spaces.
+	TrimSpace bool
+	// Prefixes and Suffixes are removed repeatedly (see TrimPrefixes and
+	// TrimSuffixes).
+	Prefixes []string
+	Suffixes []string
+}
+
+// Sanitize applies the steps enabled in opts.
+func Sanitize[T Text](s T, opts Options) T {
+	if opts.StripControl {
+		s = StripControl(s, opts.KeepControl)
+	}
+	if opts.Newline != "" {
+		s = CanonicalizeNewlines(s, opts.Newline)
+	}
+	if opts.NFC {
+		s = NFC(s)
+	}
+	if opts.TrimSpace {
+		s = TrimSpace(s)
+	}
+	if len(opts.Prefixes) > 0 {
+		s = TrimPrefixes(s, opts.Prefixes...)
+	}
+	if len(opts.Suffixes) > 0 {
+		s = TrimSuffixes(s, opts.Suffixes...)
+	}
+	return s
+}
+
+// decodeRune is utf8.DecodeRune for both strings and byte slices.
+func decodeRune[T Text](s T) (rune, int) {
+	if len(s) > 0 && s[0] < utf8.RuneSelf {
+		return rune(s[0]), 1
+	}
+	var buf [utf8.UTFMax]byte
+	n := copy(buf[:], s[:min(len(s), utf8.UTFMax)])
+	return utf8.DecodeRune(buf[:n])
+}
+
+func decodeLastRune[T Text](s T) (rune, int) {
+	if len(s) > 0 && s[len(s)-1] < utf8.RuneSelf {
+		return rune(s[len(s)-1]), 1
+	}
+	start := max(len(s)-utf8.UTFMax, 0)
+	var buf [utf8.UTFMax]byte
+	n := copy(buf[:], s[start:])
+	return utf8.DecodeLastRune(buf[:n])
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func hasPrefix[T Text](s T, prefix string) bool {
+	if len(s) < len(prefix) {
+		return false
+	}
+	for i := 0; i < len(prefix); i++ {
+		if s[i] != prefix[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func hasSuffix[T Text](s T, suffix string) bool {
+	if len(s) < len(suffix) {
+		return false
+	}
+	offset := len(s) - len(suffix)
+	for i := 0; i < len(suffix); i++ {
+		if s[offset+i] != suffix[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// TrimPrefixes removes any of the prefixes, as long as one matches; the
+// first matching prefix in the list wins.
+// Contrary to strings.TrimLeft, the prefixes are strings, not sets of
+// characters. Empty prefixes are ignored.
+func TrimPrefixes[T Text](s T, prefixes ...string) T {
+	for {
+		trimmed := false
+		for _, p := range prefixes {
+			if p != "" && hasPrefix(s, p) {
+				s = s[len(p):]
+				trimmed = true
+				break
+			}
+		}
+		if !trimmed {
+			return s
+		}
+	}
+}
+
+// TrimSuffixes removes any of the suffixes, as long as one matches; the
+// first matching suffix in the list wins. For
+// example, TrimSuffixes(s, "\r\n", "\n") removes all the trailing line
+// endings. Empty suffixes are ignored.
+func TrimSuffixes[T Text](s T, suffixes ...string) T {
+	for {
+		trimmed := false
+		for _, suffix := range suffixes {
+			if suffix != "" && hasSuffix(s, suffix) {
+				s = s[:len(s)-len(suffix)]
+				trimmed = true
+				break
+			}
+		}
+		if !trimmed {
+			return s
+		}
+	}
+}
+
+// TrimSpace removes the leading and trailing white spaces, as defined by
+// unicode.IsSpace.
+func TrimSpace[T Text](s T) T {
+	for len(s) > 0 {
+		r, size := decodeRune(s)
+		if !unicode.IsSpace(r) {
+			break
+		}
+		s = s[size:]
+	}
+	for len(s) > 0 {
+		r, size := decodeLastRune(s)
+		if !unicode.IsSpace(r) {
+			break
+		}
+		s = s[:len(s)-size]
+	}
+	return s
+}
+
+// NFC returns the text in the Unicode normalization form C, e.g. an "e"
+// followed by a combining acute accent becomes a single "é".
+func NFC[T Text](s T) T {
+	if b, ok := any(s).([]byte); ok {
+		if norm.NFC.IsNormal(b) {
+			return s
+		}
+		return T(norm.NFC.Bytes(b))
+	}
+	// Free for strings; a named byte slice type is copied
+	str := string(s)
+	if norm.NFC.IsNormalString(str) {
+		return s
+	}
+	return T(norm.NFC.String(str))
+}
+
+func keep(r rune, control string) bool {
+	if !unicode.IsControl(r) {
+		return true
+	}
+	for _, c := range control {
+		if c == r {
+			return true
+		}
+	}
+	return false
+}
+
+// StripControl removes the control characters (the Unicode Cc category, e.g.
+// NUL, ESC or DEL) except those in keep. The format characters, such as
+// the zero width joiner used by emojis, are kept. Invalid UTF-8 bytes are
+// kept as is.
+func StripControl[T Text](s T, keepControl string) T {
+	var buf []byte
+	last := 0
+	for i := 0; i < len(s); {
+		r, size := decodeRune(s[i:])
+		if r == utf8.RuneError && size == 1 || keep(r, keepControl) {
+			i += size
+			continue
+		}
+		buf = append(buf, s[last:i]...)
+		i += size
+		last = i
+	}
+	if buf == nil && last == 0 {
+		return s
+	}
+	return T(append(buf, s[last:]...))
+}
+
+// CanonicalizeNewlines replaces every line ending ("\r\n", "\r" or "\n") by
+// nl.
+func CanonicalizeNewlines[T Text](s T, nl Newline) T {
+	var buf []byte
+	last := 0
+	// Not buf == nil: replacing a leading line ending by an empty nl leaves
+	// buf nil
+	changed := false
+	for i := 0; i < len(s); i++ {
+		var size int
+		switch {
+		case s[i] == '\n':
+			size = 1
+		case s[i] == '\r' && i+1 < len(s) && s[i+1] == '\n':
+			size = 2
+		case s[i] == '\r':
+			size = 1
+		default:
+			continue
+		}
+		if string(nl) == "\r\n" && size == 2 || len(nl) == 1 && size == 1 && s[i] == nl[0] {
+			i += size - 1
+			continue
+		}
+		buf = append(buf, s[last:i]...)
+		buf = append(buf, nl...)
+		i += size - 1
+		last = i + 1
+		changed = true
+	}
+	if !changed {
+		return s
+	}
+	return T(append(buf, s[last:]...))
+}
@@ -0,0 +1,176 @@
+package sanitize
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"testing/quick"
+)
+
+// check runs f on a string and on a []byte and compares both results.
+func check(t *testing.T, name string, input, expected string, fs func(string) string, fb func([]byte) []byte) {
+	t.Helper()
+	if got := fs(input); got != expected {
+		t.Errorf("%s(%q) string: got %q, expected %q", name, input, got, expected)
+	}
+	b := []byte(input)
+	if got := fb(b); string(got) != expected {
+		t.Errorf("%s(%q) []byte: got %q, expected %q", name, input, got, expected)
+	}
+	if string(b) != input {
+		t.Errorf("%s(%q): input modified", name, input)
+	}
+}
+
+func TestTrimSuffixes(t *testing.T) {
+	// The removeNewLineSuffixes cases
+	tests := map[string]string{
+		"":          "",
+		"a\r\n":     "a",
+		"a\n":       "a",
+		"a\n\n\n":   "a",
+		"a\r\n\n":   "a",
+		"a\n\rb\n":  "a\n\rb",
+		"\r\n\r\n":  "",
+		"a\n\r":     "a\n\r",
+		"123oxoxo":  "123oxoxo",
+		"é\r\nnope": "é\r\nnope",
+	}
+	for input, expected := range tests {
+		check(t, "TrimSuffixes", input, expected,
+			func(s string) string { return TrimSuffixes(s, "\r\n", "\n") },
+			func(b []byte) []byte { return TrimSuffixes(b, "\r\n", "\n") },
+		)
+	}
+}
+
+func TestTrimPrefixes(t *testing.T) {
+	// Unlike strings.TrimLeft("oxo123", "ox"), which returns "123"
+	check(t, "TrimPrefixes", "oxo123", "o123",
+		func(s string) string { return TrimPrefixes(s, "ox") },
+		func(b []byte) []byte { return TrimPrefixes(b, "ox") },
+	)
+	check(t, "TrimPrefixes", "oxoxab123", "123",
+		func(s string) string { return TrimPrefixes(s, "ox", "ab", "") },
+		func(b []byte) []byte { return TrimPrefixes(b, "ox", "ab", "") },
+	)
+}
+
+func TestTrimSpace_MatchesStrings(t *testing.T) {
+	f := func(s string) bool {
+		return TrimSpace(s) == strings.TrimSpace(s) &&
+			bytes.Equal(TrimSpace([]byte(s)), bytes.TrimSpace([]byte(s)))
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+	check(t, "TrimSpace", "  foo　\n", "foo", TrimSpace[string], TrimSpace[[]byte])
+}
+
+func TestNFC(t *testing.T) {
+	tests := map[string]string{
+		// e and a combining acute accent
+		"e\u0301":        "\u00e9",
+		"\u00e9":         "\u00e9",
+		"hello":          "hello",
+		"A\u030a ring":   "\u00c5 ring",
+		"\u1100\u1161":   "\uac00",
+		"\u212b ngstrom": "\u00c5 ngstrom",
+	}
+	for input, expected := range tests {
+		check(t, "NFC", input, expected, NFC[string], NFC[[]byte])
+	}
+
+	type named string
+	if got := NFC(named("e\u0301")); got != "\u00e9" {
+		t.Errorf("named type: got %q", got)
+	}
+}
+
+func TestStripControl(t *testing.T) {
+	tests := []struct {
+		input    string
+		keep     string
+		expected string
+	}{
+		{"foo", "", "foo"},
+		{"\x00f\x1bo\x7fo\u0085", "", "foo"},
+		{"a\tb\nc\r", "\t\n", "a\tb\nc"},
+		{"👨\u200d👩", "", "👨\u200d👩"},
+		{"\xff\x00bad", "", "\xffbad"},
+		{"\x00\x01", "", ""},
+	}
+	for _, tt := range tests {
+		check(t, "StripControl", tt.input, tt.expected,
+			func(s string) string { return StripControl(s, tt.keep) },
+			func(b []byte) []byte { return StripControl(b, tt.keep) },
+		)
+	}
+}
+
+func TestCanonicalizeNewlines(t *testing.T) {
+	const input = "a\r\nb\nc\rd\n\re"
+	tests := map[Newline]string{
+		LF:   "a\nb\nc\nd\n\ne",
+		CRLF: "a\r\nb\r\nc\r\nd\r\n\r\ne",
+		CR:   "a\rb\rc\rd\r\re",
+		" ":  "a b c d  e",
+	}
+	for nl, expected := range tests {
+		check(t, "CanonicalizeNewlines", input, expected,
+			func(s string) string { return CanonicalizeNewlines(s, nl) },
+			func(b []byte) []byte { return CanonicalizeNewlines(b, nl) },
+		)
+	}
+	check(t, "CanonicalizeNewlines", "a\nb", "a\nb",
+		func(s string) string { return CanonicalizeNewlines(s, LF) },
+		func(b []byte) []byte { return CanonicalizeNewlines(b, LF) },
+	)
+	for input, expected := range map[string]string{"\nabc": "abc", "\r\n": "", "a\nb": "ab"} {
+		check(t, "CanonicalizeNewlines", input, expected,
+			func(s string) string { return CanonicalizeNewlines(s, "") },
+			func(b []byte) []byte { return CanonicalizeNewlines(b, "") },
+		)
+	}
+}
+
+func TestSanitize(t *testing.T) {
+	opts := Options{
+		StripControl: true,
+		KeepControl:  "\r\n",
+		Newline:      LF,
+		NFC:          true,
+		TrimSpace:    true,
+		Prefixes:     []string{"> "},
+		Suffixes:     []string{"\n"},
+	}
+	const input = "  > > cafe\x1b\u0301\r\n\x00ok\r\n\r\n "
+	check(t, "Sanitize", input, "caf\u00e9\nok",
+		func(s string) string { return Sanitize(s, opts) },
+		func(b []byte) []byte { return Sanitize(b, opts) },
+	)
+}
+
+func TestUnchangedShareMemory(t *testing.T) {
+	b := []byte("clean text")
+	for name, got := range map[string][]byte{
+		"StripControl":         StripControl(b, ""),
+		"CanonicalizeNewlines": CanonicalizeNewlines(b, LF),
+		"NFC":                  NFC(b),
+	} {
+		if &got[0] != &b[0] {
+			t.Errorf("%s: unexpected copy", name)
+		}
+	}
+}
+
+var global []byte
+
+func BenchmarkSanitize(b *testing.B) {
+	opts := Options{NFC: true, StripControl: true, KeepControl: "\n", Newline: LF, TrimSpace: true}
+	input := []byte(strings.Repeat("Hêllo, wörld!\r\n", 1000))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		global = Sanitize(input, opts)
+	}
+}
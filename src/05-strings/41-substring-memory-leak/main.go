@@ -4,6 +4,8 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+
+	"github.com/teivah/100-go-mistakes/src/05-strings/37-string-iteration/runeindex"
 )
 
 func main() {
@@ -14,6 +16,10 @@ func main() {
 	s1 = "Hêllo, World!"
 	s2 = string([]rune(s1)[:5])
 	fmt.Println(s2)
+
+	// Same result without converting the whole string into a []rune
+	s2 = strings.Clone(runeindex.New(s1).Slice(0, 5))
+	fmt.Println(s2)
 }
 
 type store struct{}
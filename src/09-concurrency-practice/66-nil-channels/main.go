@@ -1,5 +1,7 @@
 package main
 
+import "github.com/teivah/100-go-mistakes/src/04-control-structures/31-range-loop-arg-evaluation/channels/mux"
+
 func merge1(ch1, ch2 <-chan int) <-chan int {
 	ch := make(chan int, 1)
 
@@ -91,3 +93,9 @@ func merge4(ch1, ch2 <-chan int) <-chan int {
 
 	return ch
 }
+
+// merge5 is merge4 relying on the mux package, which also allows changing
+// the sources while merging.
+func merge5(ch1, ch2 <-chan int) <-chan int {
+	return mux.Merge(mux.Fair, 1, ch1, ch2)
+}
@@ -3,14 +3,17 @@ package main
 import (
 	"fmt"
 	"sync"
+
+	"github.com/teivah/100-go-mistakes/src/03-data-types/19-floating-points/money"
 )
 
 func main() {
 	c := Cache{
-		balances: make(map[string]float64),
+		currency: money.EUR,
+		balances: make(map[string]money.Money),
 	}
-	c.AddBalance("1", 1.0)
-	c.AddBalance("2", 3.0)
+	c.AddBalance("1", money.FromMinor(100, money.EUR))
+	c.AddBalance("2", money.FromMinor(300, money.EUR))
 	fmt.Println(c.AverageBalance1())
 	fmt.Println(c.AverageBalance2())
 	fmt.Println(c.AverageBalance3())
@@ -18,49 +21,48 @@ func main() {
 
 type Cache struct {
 	mu       sync.RWMutex
-	balances map[string]float64
+	currency money.Currency
+	balances map[string]money.Money
 }
 
-func (c *Cache) AddBalance(id string, balance float64) {
+func (c *Cache) AddBalance(id string, balance money.Money) {
 	c.mu.Lock()
 	c.balances[id] = balance
 	c.mu.Unlock()
 }
 
-func (c *Cache) AverageBalance1() float64 {
+func (c *Cache) AverageBalance1() (money.Money, error) {
 	c.mu.RLock()
 	balances := c.balances
 	c.mu.RUnlock()
 
-	sum := 0.
-	for _, balance := range balances {
-		sum += balance
-	}
-	return sum / float64(len(balances))
+	return average(c.currency, balances)
 }
 
-func (c *Cache) AverageBalance2() float64 {
+func (c *Cache) AverageBalance2() (money.Money, error) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
-	sum := 0.
-	for _, balance := range c.balances {
-		sum += balance
-	}
-	return sum / float64(len(c.balances))
+	return average(c.currency, c.balances)
 }
 
-func (c *Cache) AverageBalance3() float64 {
+func (c *Cache) AverageBalance3() (money.Money, error) {
 	c.mu.RLock()
-	m := make(map[string]float64, len(c.balances))
+	m := make(map[string]money.Money, len(c.balances))
 	for k, v := range c.balances {
 		m[k] = v
 	}
 	c.mu.RUnlock()
 
-	sum := 0.
-	for _, balance := range m {
-		sum += balance
+	return average(c.currency, m)
+}
+
+// average iterates over the balances, so it has to be called with the map
+// protected from concurrent writes.
+func average(currency money.Currency, balances map[string]money.Money) (money.Money, error) {
+	amounts := make([]money.Money, 0, len(balances))
+	for _, balance := range balances {
+		amounts = append(amounts, balance)
 	}
-	return sum / float64(len(m))
+	return money.Average(currency, amounts...)
 }